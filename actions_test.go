@@ -0,0 +1,155 @@
+package mira_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestSubmissionActions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		do   func(q mira.Queued) error
+		path string
+		form map[string]string
+		// marked is whether the post starts out saved, hidden, NSFW & spoilered
+		marked bool
+		check  func(p *models.Post) bool
+		onlyOn models.RedditKind
+	}{
+		{name: "upvote", do: mira.Queued.Upvote, path: "/api/vote", form: map[string]string{"dir": "1"},
+			check: func(p *models.Post) bool { return p.Likes }},
+		{name: "downvote", do: mira.Queued.Downvote, path: "/api/vote", form: map[string]string{"dir": "-1"}},
+		{name: "unvote", do: mira.Queued.Unvote, path: "/api/vote", form: map[string]string{"dir": "0"}},
+		{name: "save", do: func(q mira.Queued) error { return q.Save("") }, path: "/api/save",
+			check: func(p *models.Post) bool { return p.Saved }},
+		{name: "save with category", do: func(q mira.Queued) error { return q.Save("memes") }, path: "/api/save",
+			form: map[string]string{"category": "memes"}},
+		{name: "unsave", marked: true, do: mira.Queued.Unsave, path: "/api/unsave",
+			check: func(p *models.Post) bool { return !p.Saved }},
+		{name: "hide", do: mira.Queued.Hide, path: "/api/hide", onlyOn: models.KPost,
+			check: func(p *models.Post) bool { return p.Hidden }},
+		{name: "unhide", marked: true, do: mira.Queued.Unhide, path: "/api/unhide", onlyOn: models.KPost,
+			check: func(p *models.Post) bool { return !p.Hidden }},
+		{name: "mark nsfw", do: mira.Queued.MarkNSFW, path: "/api/marknsfw", onlyOn: models.KPost,
+			check: func(p *models.Post) bool { return p.Over18 }},
+		{name: "unmark nsfw", marked: true, do: mira.Queued.UnmarkNSFW, path: "/api/unmarknsfw", onlyOn: models.KPost,
+			check: func(p *models.Post) bool { return !p.Over18 }},
+		{name: "spoiler", do: mira.Queued.Spoiler, path: "/api/spoiler", onlyOn: models.KPost,
+			check: func(p *models.Post) bool { return p.Spoiler }},
+		{name: "unspoiler", marked: true, do: mira.Queued.Unspoiler, path: "/api/unspoiler", onlyOn: models.KPost,
+			check: func(p *models.Post) bool { return !p.Spoiler }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			post := srv.AddPost(&models.Post{Subreddit: "test", Title: "Hello",
+				Saved: tc.marked, Hidden: tc.marked, Over18: tc.marked, Spoiler: tc.marked})
+			comment := srv.AddComment(&models.Comment{LinkID: post.Name, ParentID: post.Name, Subreddit: "test", Body: "Hi"})
+
+			if err := tc.do(reddit.Post(string(post.Name))); err != nil {
+				t.Fatal(err)
+			}
+			err := tc.do(reddit.Comment(string(comment.Name)))
+			if tc.onlyOn == models.KPost && err == nil {
+				t.Error("succeeded on a comment")
+			} else if tc.onlyOn == "" && err != nil {
+				t.Errorf("failed on a comment: %v", err)
+			}
+			if err := tc.do(reddit.Subreddit("test")); err == nil {
+				t.Error("succeeded on a subreddit")
+			}
+
+			actions := srv.ActionsTo(tc.path)
+			want := []models.RedditID{post.Name}
+			if tc.onlyOn == "" {
+				want = append(want, comment.Name)
+			}
+			if len(actions) != len(want) {
+				t.Fatalf("got %d requests to %s, want %d", len(actions), tc.path, len(want))
+			}
+			for i, a := range actions {
+				if a.Form.Get("id") != string(want[i]) {
+					t.Errorf("request %d is for %s, want %s", i, a.Form.Get("id"), want[i])
+				}
+				for k, v := range tc.form {
+					if a.Form.Get(k) != v {
+						t.Errorf("request %d: %s = %q, want %q", i, k, a.Form.Get(k), v)
+					}
+				}
+			}
+			if tc.name == "save" && actions[0].Form.Has("category") {
+				t.Errorf("sent category %q, want none", actions[0].Form.Get("category"))
+			}
+			if p := srv.Submission(post.Name).(*models.Post); tc.check != nil && !tc.check(p) {
+				t.Errorf("%s wasn't changed", post.Name)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		reason mira.ReportReason
+		form   map[string]string
+		// report is the reason the report is listed with
+		report string
+	}{
+		{name: "rule", reason: mira.ReportReason{Rule: "No memes"},
+			form: map[string]string{"reason": "No memes", "rule_reason": "No memes"}, report: "No memes"},
+		{name: "site", reason: mira.ReportReason{Site: "This is spam"},
+			form: map[string]string{"reason": "This is spam", "site_reason": "This is spam"}, report: "This is spam"},
+		{name: "other", reason: mira.ReportReason{Other: "looks fishy"},
+			form: map[string]string{"reason": "other", "other_reason": "looks fishy"}, report: "looks fishy"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			post := srv.AddPost(&models.Post{Subreddit: "test", Title: "Hello"})
+			if err := reddit.Post(string(post.Name)).Report(tc.reason); err != nil {
+				t.Fatal(err)
+			}
+			actions := srv.ActionsTo("/api/report")
+			if len(actions) != 1 {
+				t.Fatalf("got %d reports, want 1", len(actions))
+			}
+			if actions[0].Form.Get("thing_id") != string(post.Name) {
+				t.Errorf("reported %s, want %s", actions[0].Form.Get("thing_id"), post.Name)
+			}
+			for k, v := range tc.form {
+				if actions[0].Form.Get(k) != v {
+					t.Errorf("%s = %q, want %q", k, actions[0].Form.Get(k), v)
+				}
+			}
+			reports := srv.Submission(post.Name).GetReports()
+			if reports.Num != 1 || len(reports.User) != 1 || reports.User[0].Reason != tc.report {
+				t.Errorf("got reports %+v, want one for %q", reports, tc.report)
+			}
+		})
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		reason mira.ReportReason
+		err    string
+	}{
+		{name: "no reason", err: "exactly one reason"},
+		{name: "two reasons", reason: mira.ReportReason{Rule: "No memes", Other: "fishy"}, err: "exactly one reason"},
+		{name: "too long", reason: mira.ReportReason{Other: strings.Repeat("a", 101)}, err: "up to 100 characters long, got 101"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			post := srv.AddPost(&models.Post{Subreddit: "test", Title: "Hello"})
+			err := reddit.Post(string(post.Name)).Report(tc.reason)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want it to contain %q", err, tc.err)
+			}
+			if n := len(srv.Actions()); n != 0 {
+				t.Errorf("got %d actions, want none", n)
+			}
+		})
+	}
+}
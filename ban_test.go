@@ -0,0 +1,159 @@
+package mira_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestBan(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ban  func(q mira.Queued) error
+		form map[string]string
+		// days is the ban duration reddit lists, 0 for permanent bans
+		days int
+	}{
+		{
+			name: "temporary",
+			ban: func(q mira.Queued) error {
+				return q.BanWithOptions("spammer", mira.BanOptions{
+					Duration: 7, Reason: "Spam", Note: "same link 20 times", Message: "Read the rules", Context: "t3_abc",
+				})
+			},
+			form: map[string]string{
+				"name": "spammer", "type": "banned", "duration": "7", "ban_reason": "Spam",
+				"note": "same link 20 times", "ban_message": "Read the rules", "ban_context": "t3_abc",
+			},
+			days: 7,
+		},
+		{
+			name: "permanent",
+			ban: func(q mira.Queued) error {
+				return q.BanWithOptions("spammer", mira.BanOptions{Reason: "Spam"})
+			},
+			form: map[string]string{"name": "spammer", "type": "banned", "ban_reason": "Spam"},
+		},
+		{
+			name: "reason as note",
+			ban: func(q mira.Queued) error {
+				return q.Ban("spammer", 3, "t1_abc", "Read the rules", "Spam")
+			},
+			form: map[string]string{
+				"name": "spammer", "type": "banned", "duration": "3", "ban_reason": "Spam",
+				"note": "Spam", "ban_message": "Read the rules", "ban_context": "t1_abc",
+			},
+			days: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			sub := reddit.Subreddit("test")
+			if err := tc.ban(sub); err != nil {
+				t.Fatal(err)
+			}
+			actions := srv.ActionsTo("/r/test/api/friend")
+			if len(actions) != 1 {
+				t.Fatalf("got %d requests to /r/test/api/friend, want 1", len(actions))
+			}
+			for k, v := range tc.form {
+				if got := actions[0].Form.Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+			if tc.days == 0 && actions[0].Form.Has("duration") {
+				t.Errorf("sent duration %q for a permanent ban", actions[0].Form.Get("duration"))
+			}
+
+			ban, err := sub.BanInfo("SPAMMER")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ban == nil {
+				t.Fatal("spammer isn't banned")
+			}
+			if tc.days == 0 {
+				if !ban.IsPermanent() || !ban.Expires.IsZero() {
+					t.Errorf("got ban with %v days left expiring %s, want a permanent one", ban.DaysLeft, ban.Expires)
+				}
+				return
+			}
+			if ban.IsPermanent() || *ban.DaysLeft != tc.days {
+				t.Fatalf("got ban with %v days left, want %d", ban.DaysLeft, tc.days)
+			}
+			if want := time.Now().AddDate(0, 0, tc.days); ban.Expires.Sub(want).Abs() > time.Minute {
+				t.Errorf("ban expires %s, want about %s", ban.Expires, want)
+			}
+		})
+	}
+}
+
+func TestBanErrors(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	for _, days := range []int{-1, 1000} {
+		err := reddit.Subreddit("test").BanWithOptions("spammer", mira.BanOptions{Duration: days})
+		if err == nil || !strings.Contains(err.Error(), "ban duration must be 0 (permanent) to 999 days") {
+			t.Errorf("banning for %d days: got error %v", days, err)
+		}
+	}
+	if err := reddit.Redditor("someone").BanWithOptions("spammer", mira.BanOptions{}); err == nil {
+		t.Error("banning from a redditor succeeded")
+	}
+	if n := len(srv.Actions()); n != 0 {
+		t.Errorf("got %d actions, want none", n)
+	}
+
+	ban, err := reddit.Subreddit("test").BanInfo("nobody")
+	if err != nil || ban != nil {
+		t.Errorf("got ban %+v & error %v for a redditor that isn't banned", ban, err)
+	}
+}
+
+func TestUnban(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.AddRelationship("test", "banned", &models.Relationship{Name: "spammer"})
+	if err := reddit.Subreddit("test").Unban("spammer"); err != nil {
+		t.Fatal(err)
+	}
+	actions := srv.ActionsTo("/r/test/api/unfriend")
+	if len(actions) != 1 || actions[0].Form.Get("name") != "spammer" || actions[0].Form.Get("type") != "banned" {
+		t.Fatalf("got unfriend requests %+v, want one for banned spammer", actions)
+	}
+	if n := len(srv.Relationships("test", "banned")); n != 0 {
+		t.Errorf("%d redditors are still banned", n)
+	}
+}
+
+func TestMute(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	sub := reddit.Subreddit("test")
+	if err := sub.Mute("shouter", "all caps"); err != nil {
+		t.Fatal(err)
+	}
+	actions := srv.ActionsTo("/r/test/api/friend")
+	if len(actions) != 1 {
+		t.Fatalf("got %d requests to /r/test/api/friend, want 1", len(actions))
+	}
+	for k, v := range map[string]string{"name": "shouter", "type": "muted", "note": "all caps"} {
+		if got := actions[0].Form.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if muted := srv.Relationships("test", "muted"); len(muted) != 1 || muted[0].Note != "all caps" {
+		t.Fatalf("got muted %+v, want shouter", muted)
+	}
+
+	if err := sub.Unmute("shouter"); err != nil {
+		t.Fatal(err)
+	}
+	actions = srv.ActionsTo("/r/test/api/unfriend")
+	if len(actions) != 1 || actions[0].Form.Get("name") != "shouter" || actions[0].Form.Get("type") != "muted" {
+		t.Fatalf("got unfriend requests %+v, want one for muted shouter", actions)
+	}
+	if n := len(srv.Relationships("test", "muted")); n != 0 {
+		t.Errorf("%d redditors are still muted", n)
+	}
+}
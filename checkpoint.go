@@ -0,0 +1,137 @@
+package mira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Checkpoint is the position of a stream: the newest item it delivered.
+type Checkpoint struct {
+	Last    models.RedditID `json:"last"`
+	Created time.Time       `json:"created"`
+}
+
+// CheckpointStore saves the Checkpoints of streams, so they can resume after a restart.
+// Set it in StreamOptions. Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Load returns the Checkpoint saved for key, or an empty Checkpoint if there is none.
+	Load(key string) (Checkpoint, error)
+	// Save stores the Checkpoint for key.
+	Save(key string, cp Checkpoint) error
+}
+
+// MemoryCheckpoints keeps Checkpoints in memory. This is useful to restart streams within
+// one process, and in tests.
+type MemoryCheckpoints struct {
+	mu  sync.Mutex
+	cps map[string]Checkpoint
+}
+
+// NewMemoryCheckpoints creates an empty MemoryCheckpoints store.
+func NewMemoryCheckpoints() *MemoryCheckpoints {
+	return &MemoryCheckpoints{cps: make(map[string]Checkpoint)}
+}
+
+// Load implements CheckpointStore.
+func (m *MemoryCheckpoints) Load(key string) (Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cps[key], nil
+}
+
+// Save implements CheckpointStore.
+func (m *MemoryCheckpoints) Save(key string, cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cps[key] = cp
+	return nil
+}
+
+// FileCheckpoints keeps Checkpoints in a JSON file. The file is created on the first Save,
+// and replaced atomically on every Save, so it is never left half-written.
+// Multiple streams can share a file, but not multiple processes.
+type FileCheckpoints struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpoints creates a store that saves Checkpoints to the file at path.
+func NewFileCheckpoints(path string) *FileCheckpoints {
+	return &FileCheckpoints{path: path}
+}
+
+// Load implements CheckpointStore.
+func (f *FileCheckpoints) Load(key string) (Checkpoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cps, err := f.read()
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	return cps[key], nil
+}
+
+// Save implements CheckpointStore.
+func (f *FileCheckpoints) Save(key string, cp Checkpoint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cps, err := f.read()
+	if err != nil {
+		return err
+	}
+	cps[key] = cp
+	data, err := json.MarshalIndent(cps, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *FileCheckpoints) read() (map[string]Checkpoint, error) {
+	cps := make(map[string]Checkpoint)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cps, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cps); err != nil {
+		return nil, err
+	}
+	return cps, nil
+}
+
+// backfill pages through the listing at target and returns everything newer than cp, newest first.
+// At most max items are returned.
+func backfill[T models.RedditThing](ctx context.Context, c *Reddit, target string, params map[string]string, cp Checkpoint, max int) ([]T, error) {
+	it := NewIterator[T](ctx, c, target, params, IteratorOptions{Max: max, Since: cp.Created})
+	var ret []T
+	for it.Next() {
+		if it.Item().GetID() == cp.Last {
+			break
+		}
+		ret = append(ret, it.Item())
+	}
+	return ret, it.Err()
+}
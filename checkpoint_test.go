@@ -0,0 +1,150 @@
+package mira_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestFileCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoints.json")
+	store := mira.NewFileCheckpoints(path)
+
+	if cp, err := store.Load("posts/r/test"); err != nil || cp.Last != "" {
+		t.Fatalf("Load without a file returned %+v, %v; want an empty Checkpoint", cp, err)
+	}
+	want := mira.Checkpoint{Last: "t3_abc", Created: time.Unix(1700000000, 0).UTC()}
+	if err := store.Save("posts/r/test", want); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("inbox/inbox", mira.Checkpoint{Last: "t4_def"}); err != nil {
+		t.Fatal(err)
+	}
+	// the temporary file has been renamed
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory contains %d files, want only the checkpoints", len(entries))
+	}
+
+	// as if the bot restarted
+	store = mira.NewFileCheckpoints(path)
+	if cp, err := store.Load("posts/r/test"); err != nil || cp.Last != want.Last || !cp.Created.Equal(want.Created) {
+		t.Errorf("Load returned %+v, %v; want %+v", cp, err, want)
+	}
+	if cp, err := store.Load("inbox/inbox"); err != nil || cp.Last != "t4_def" {
+		t.Errorf("Load returned %+v, %v; want the second checkpoint", cp, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"posts/r/test": {"last": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load("posts/r/test"); err == nil {
+		t.Error("Load of a corrupt file succeeded")
+	}
+	// a corrupt file isn't replaced, the other checkpoints in it would be lost
+	if err := store.Save("posts/r/test", want); err == nil {
+		t.Error("Save to a corrupt file succeeded")
+	}
+}
+
+func TestStreamResume(t *testing.T) {
+	tests := []struct {
+		name     string
+		backfill int
+		// want are the indexes of the posts added during the downtime that are sent
+		want []int
+	}{
+		{name: "everything", want: []int{0, 1, 2, 3, 4}},
+		{name: "limited", backfill: 3, want: []int{2, 3, 4}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			created := float64(time.Now().Add(-time.Hour).Unix())
+			post := func() *models.Post {
+				created++
+				return srv.AddPost(&models.Post{Subreddit: "test", CreatedUTC: created})
+			}
+			path := filepath.Join(t.TempDir(), "checkpoints.json")
+			opts := fastStream
+			// the first poll only sees 2 posts, everything else has to be backfilled
+			opts.PageSize = 2
+			opts.Backfill = tc.backfill
+			opts.Checkpoints = mira.NewFileCheckpoints(path)
+
+			post()
+			last := post()
+			stream, err := reddit.Subreddit("test").WithStreamOptions(opts).StreamPosts()
+			if err != nil {
+				t.Fatal(err)
+			}
+			receive(t, stream.C, 2)
+			stopStream(t, stream.Close, stream.C, stream.Errors)
+			if cp, _ := opts.Checkpoints.Load("posts/r/test"); cp.Last != last.Name {
+				t.Fatalf("saved checkpoint %s, want %s", cp.Last, last.Name)
+			}
+
+			var missed []*models.Post
+			for i := 0; i < 5; i++ {
+				missed = append(missed, post())
+			}
+			// a new process with a new store
+			reddit, err = srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.Checkpoints = mira.NewFileCheckpoints(path)
+			stream, err = reddit.Subreddit("test").WithStreamOptions(opts).StreamPosts()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, p := range receive(t, stream.C, len(tc.want)) {
+				if want := missed[tc.want[i]]; p.GetID() != want.Name {
+					t.Errorf("post %d is %s, want %s", i, p.GetID(), want.Name)
+				}
+			}
+			receiveNone(t, stream.C)
+			newer := post()
+			if got := receive(t, stream.C, 1); got[0].GetID() != newer.Name {
+				t.Errorf("got %s, want %s", got[0].GetID(), newer.Name)
+			}
+			stopStream(t, stream.Close, stream.C, stream.Errors)
+			if cp, _ := opts.Checkpoints.Load("posts/r/test"); cp.Last != newer.Name {
+				t.Errorf("saved checkpoint %s, want %s", cp.Last, newer.Name)
+			}
+		})
+	}
+}
+
+// If the last item delivered is gone, the stream resumes at the time it was created.
+func TestStreamResumeDeleted(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	var posts []*models.Post
+	for i := 0; i < 6; i++ {
+		posts = append(posts, srv.AddPost(&models.Post{Subreddit: "test", CreatedUTC: float64(base.Unix() + int64(i))}))
+	}
+	opts := fastStream
+	opts.PageSize = 2
+	opts.Checkpoints = mira.NewMemoryCheckpoints()
+	opts.CheckpointKey = "my-bot"
+	if err := opts.Checkpoints.Save("my-bot", mira.Checkpoint{Last: "t3_gone", Created: base.Add(2500 * time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := reddit.Subreddit("test").WithStreamOptions(opts).StreamPosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range receive(t, stream.C, 3) {
+		if want := posts[3+i]; p.GetID() != want.Name {
+			t.Errorf("post %d is %s, want %s", i, p.GetID(), want.Name)
+		}
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
@@ -0,0 +1,30 @@
+package mira
+
+import (
+	"context"
+	"time"
+)
+
+// clock tells the time and waits for it to pass. Rate limits & streams use it instead of the
+// time package, so tests can control time.
+type clock interface {
+	Now() time.Time
+	// After is like time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the clock of the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// sleepClock waits for d to pass on c, or until ctx is done.
+func sleepClock(ctx context.Context, c clock, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.After(d):
+		return nil
+	}
+}
@@ -1,7 +1,42 @@
 package mira
 
+import "strings"
+
 // RedditBase is the basic reddit URL, RedditOauth is the base URL for use once authenticated
 const (
 	RedditBase  = "https://www.reddit.com/"
 	RedditOauth = "https://oauth.reddit.com"
 )
+
+// Endpoints defines the URLs mira uses to talk to reddit. You can change them
+// via Credentials, i.e. to run your bot against a local fake of the reddit API
+// (see package miratest). Empty values are replaced by the defaults.
+type Endpoints struct {
+	// Base is the reddit URL used for authentication, defaults to RedditBase.
+	Base string
+	// OAuth is the base URL for all API calls once authenticated, defaults to RedditOauth.
+	OAuth string
+	// AuthURL & TokenURL are used for the OAuth flow. They default to
+	// /api/v1/authorize & /api/v1/access_token on Base.
+	AuthURL  string
+	TokenURL string
+}
+
+// withDefaults fills in all empty endpoints.
+func (e Endpoints) withDefaults() Endpoints {
+	if e.Base == "" {
+		e.Base = RedditBase
+	}
+	if e.OAuth == "" {
+		e.OAuth = RedditOauth
+	}
+	e.OAuth = strings.TrimSuffix(e.OAuth, "/")
+	base := strings.TrimSuffix(e.Base, "/")
+	if e.AuthURL == "" {
+		e.AuthURL = base + "/api/v1/authorize"
+	}
+	if e.TokenURL == "" {
+		e.TokenURL = base + "/api/v1/access_token"
+	}
+	return e
+}
@@ -13,4 +13,18 @@
 //  |   t4   | Message                          |
 //  |   t5   | Subreddit                        |
 //  |   t6   | Award                            |
+//
+// Queued Handles
+//
+// Older versions of mira queued objects in a channel shared by the whole Reddit instance, so the
+// object & the action could be selected in separate statements. This was not safe for concurrent
+// use and has been removed: Subreddit(), Post(), Comment(), Message(), Redditor() and Me() now
+// return a Queued handle, and all actions are methods of it. Chained calls work as before, but
+// code calling actions on the Reddit instance itself has to keep the handle instead:
+//  // before
+//  reddit.Subreddit("iama")
+//  posts, err := reddit.Posts("new", "all", 10)
+//  // now
+//  posts, err := reddit.Subreddit("iama").Posts("new", "all", 10)
+// Reddit.Chain no longer exists.
 package mira
@@ -0,0 +1,239 @@
+package mira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is returned if reddit answers a request with an error.
+// Depending on the HTTP status, one of the more specific errors below is
+// returned instead - all of them can be unwrapped to an APIError:
+//
+//	var apiErr *mira.APIError
+//	if errors.As(err, &apiErr) {
+//		fmt.Println("reddit returned", apiErr.StatusCode)
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Message & Code are taken from the response body if reddit sent them.
+	Message string
+	Code    string
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("reddit: %d %s", e.StatusCode, msg)
+	}
+	return fmt.Sprintf("reddit: %d %s | error code: %s", e.StatusCode, msg, e.Code)
+}
+
+// RateLimitError is returned if reddit answers with 429 Too Many Requests.
+type RateLimitError struct {
+	APIError
+	// RetryAfter is how long reddit wants us to wait before trying again, if known.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Unwrap() error { return &e.APIError }
+
+// ForbiddenError is returned if reddit answers with 401 Unauthorized or 403 Forbidden,
+// i.e. because the account lacks permissions or the subreddit is private.
+type ForbiddenError struct{ APIError }
+
+func (e *ForbiddenError) Unwrap() error { return &e.APIError }
+
+// NotFoundError is returned if reddit answers with 404 Not Found.
+type NotFoundError struct{ APIError }
+
+func (e *NotFoundError) Unwrap() error { return &e.APIError }
+
+// ServerError is returned if reddit answers with a 5xx status code.
+type ServerError struct {
+	APIError
+	// RetryAfter is how long reddit wants us to wait before trying again, if it sent a
+	// Retry-After header (i.e. with 503 Service Unavailable during maintenance).
+	RetryAfter time.Duration
+}
+
+func (e *ServerError) Unwrap() error { return &e.APIError }
+
+// ValidationError is returned if reddit rejects an action (submitting, commenting, ...)
+// and lists the reasons in the "json.errors" array of the response.
+type ValidationError struct {
+	APIError
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	errs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		errs = append(errs, fe.Error())
+	}
+	return "reddit: " + strings.Join(errs, ", ")
+}
+
+func (e *ValidationError) Unwrap() error { return &e.APIError }
+
+// Has tells you if reddit returned the given error code (i.e. "RATELIMIT" or "SUBREDDIT_NOEXIST").
+func (e *ValidationError) Has(code string) bool {
+	for _, fe := range e.Errors {
+		if fe.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// WikiConflictError is returned when editing a wiki page fails because someone else edited
+// it after the revision passed as WikiEditOptions.Previous.
+type WikiConflictError struct {
+	APIError
+	// NewRevision is the current revision of the page, NewContent its content.
+	NewRevision string
+	NewContent  string
+	// Diff shows the changes between your content & NewContent as HTML.
+	Diff string
+}
+
+func (e *WikiConflictError) Error() string {
+	return "reddit: wiki page has been edited in the meantime, current revision: " + e.NewRevision
+}
+
+func (e *WikiConflictError) Unwrap() error { return &e.APIError }
+
+// FieldError is a single error reported by reddit when rejecting an action.
+type FieldError struct {
+	// Code is the error code, i.e. "RATELIMIT", "SUBREDDIT_NOEXIST", "NO_TEXT"
+	Code string
+	// Message is a human readable description of the error.
+	Message string
+	// Field is the name of the form field the error belongs to, if any.
+	Field string
+}
+
+func (fe FieldError) Error() string {
+	if fe.Field == "" {
+		return fmt.Sprintf("%s: %s", fe.Code, fe.Message)
+	}
+	return fmt.Sprintf("%s: %s (field: %s)", fe.Code, fe.Message, fe.Field)
+}
+
+// UnmarshalJSON reads a FieldError from reddits [code, message, field] arrays.
+func (fe *FieldError) UnmarshalJSON(data []byte) error {
+	var v []interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	parts := make([]string, 3)
+	for i := 0; i < len(v) && i < len(parts); i++ {
+		if s, ok := v[i].(string); ok {
+			parts[i] = s
+		}
+	}
+	fe.Code, fe.Message, fe.Field = parts[0], parts[1], parts[2]
+	return nil
+}
+
+// findRedditError checks a response from reddit for errors and returns
+// the matching error type, or nil if the request was successful.
+//
+// Successful responses only count as failed if they contain reddits "json.errors" array,
+// which it uses to reject actions (submitting, commenting, ...) with a 200 status.
+func findRedditError(resp *http.Response, data []byte) error {
+	var object struct {
+		Message string      `json:"message"`
+		Error   interface{} `json:"error"`
+		// the modmail endpoints describe errors in reason & explanation
+		Reason      string `json:"reason"`
+		Explanation string `json:"explanation"`
+		// sent on wiki edit conflicts
+		NewRevision string `json:"newrevision"`
+		NewContent  string `json:"newcontent"`
+		DiffContent string `json:"diffcontent"`
+		JSON        struct {
+			Errors []FieldError `json:"errors"`
+		} `json:"json"`
+	}
+	// Not every body is an object: successful responses may be arrays (i.e. comment pages),
+	// and errors may be HTML pages from reddits load balancers. Those bodies carry no error
+	// details, only the status code counts then.
+	isObject := json.Unmarshal(data, &object) == nil
+
+	if resp.StatusCode < 400 {
+		if isObject && len(object.JSON.Errors) > 0 {
+			return &ValidationError{APIError: APIError{StatusCode: resp.StatusCode, Body: data}, Errors: object.JSON.Errors}
+		}
+		return nil
+	}
+
+	apiErr := APIError{
+		StatusCode: resp.StatusCode,
+		Message:    object.Message,
+		Body:       data,
+	}
+	if object.Error != nil {
+		apiErr.Code = fmt.Sprint(object.Error)
+	} else if object.Reason != "" {
+		apiErr.Code = object.Reason
+	}
+	if object.Explanation != "" {
+		apiErr.Message = object.Explanation
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		wait := retryAfter(resp.Header)
+		if wait == 0 {
+			// fall back to the end of the current rate limit period
+			wait = headerSeconds(resp.Header, "X-Ratelimit-Reset")
+		}
+		return &RateLimitError{APIError: apiErr, RetryAfter: wait}
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return &ForbiddenError{apiErr}
+	case resp.StatusCode == http.StatusNotFound:
+		return &NotFoundError{apiErr}
+	case resp.StatusCode == http.StatusConflict && object.Reason == "EDIT_CONFLICT":
+		return &WikiConflictError{
+			APIError:    apiErr,
+			NewRevision: object.NewRevision,
+			NewContent:  object.NewContent,
+			Diff:        object.DiffContent,
+		}
+	case resp.StatusCode >= 500:
+		return &ServerError{APIError: apiErr, RetryAfter: retryAfter(resp.Header)}
+	case len(object.JSON.Errors) > 0:
+		return &ValidationError{APIError: apiErr, Errors: object.JSON.Errors}
+	}
+	return &apiErr
+}
+
+// retryAfter reads the time to wait from the Retry-After header, which is either
+// a number of seconds or a date.
+func retryAfter(h http.Header) time.Duration {
+	if d := headerSeconds(h, "Retry-After"); d > 0 {
+		return d
+	}
+	if t, err := http.ParseTime(h.Get("Retry-After")); err == nil && time.Until(t) > 0 {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// headerSeconds reads a header containing a number of seconds.
+func headerSeconds(h http.Header, key string) time.Duration {
+	if secs, err := strconv.ParseFloat(h.Get(key), 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	return 0
+}
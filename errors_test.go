@@ -0,0 +1,102 @@
+package mira_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+)
+
+func TestErrors(t *testing.T) {
+	isType := func(target interface{}) func(error) bool {
+		return func(err error) bool { return errors.As(err, target) }
+	}
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+		// is checks the error, nil means the request has to succeed
+		is   func(error) bool
+		code string
+	}{
+		{name: "listing", status: 200, body: `{"kind": "Listing", "data": {"children": []}}`},
+		{name: "array", status: 200, body: `[{"kind": "Listing"}, {"kind": "Listing"}]`},
+		{name: "empty object", status: 200, body: `{}`},
+		{name: "payload with message", status: 200, body: `{"message": "hello", "reason": "none"}`},
+		{name: "rejected", status: 200, body: `{"json": {"errors": [["NO_TEXT", "we need something here", "text"]]}}`,
+			is: isType(new(*mira.ValidationError)), code: "NO_TEXT"},
+		{name: "rejected with 400", status: 400, body: `{"json": {"errors": [["BAD_SR_NAME", "bad name", "sr"]]}}`,
+			is: isType(new(*mira.ValidationError)), code: "BAD_SR_NAME"},
+		{name: "bad request", status: 400, body: `{"reason": "CANT_REPLY", "explanation": "can't reply", "message": "Bad Request"}`,
+			is: isType(new(*mira.APIError)), code: "CANT_REPLY"},
+		{name: "unauthorized", status: 401, body: `{"message": "Unauthorized", "error": 401}`,
+			is: isType(new(*mira.ForbiddenError)), code: "401"},
+		{name: "forbidden", status: 403, body: `{"message": "Forbidden", "error": 403}`,
+			is: isType(new(*mira.ForbiddenError)), code: "403"},
+		{name: "not found", status: 404, body: `{"message": "Not Found", "error": 404}`,
+			is: isType(new(*mira.NotFoundError)), code: "404"},
+		{name: "wiki conflict", status: 409, body: `{"reason": "EDIT_CONFLICT", "message": "Conflict", "newrevision": "abc"}`,
+			is: isType(new(*mira.WikiConflictError)), code: "EDIT_CONFLICT"},
+		{name: "other conflict", status: 409, body: `{"message": "Conflict"}`,
+			is: isType(new(*mira.APIError))},
+		{name: "rate limited", status: 429, header: map[string]string{"Retry-After": "2"}, body: `{"message": "Too Many Requests", "error": 429}`,
+			is: func(err error) bool {
+				var rlErr *mira.RateLimitError
+				return errors.As(err, &rlErr) && rlErr.RetryAfter == 2*time.Second
+			}, code: "429"},
+		{name: "server error", status: 500, body: `{"message": "Internal Server Error", "error": 500}`,
+			is: isType(new(*mira.ServerError)), code: "500"},
+		{name: "html error page", status: 503, body: `<html><body>all of our servers are busy</body></html>`,
+			is: isType(new(*mira.ServerError))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+			reddit := mira.Init(mira.Credentials{Endpoints: mira.Endpoints{Base: srv.URL, OAuth: srv.URL}})
+			reddit.Client = srv.Client()
+			reddit.Values.Retry.MaxAttempts = 1
+
+			data, err := reddit.MiraRequest("GET", srv.URL+"/test", nil)
+			if tc.is == nil {
+				if err != nil {
+					t.Fatalf("got error %v, want none", err)
+				}
+				if string(data) != tc.body {
+					t.Errorf("got body %q, want %q", data, tc.body)
+				}
+				return
+			}
+			if !tc.is(err) {
+				t.Fatalf("got error %T (%v), want another type", err, err)
+			}
+			var apiErr *mira.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("%T doesn't unwrap to an APIError", err)
+			}
+			if apiErr.StatusCode != tc.status || string(apiErr.Body) != tc.body {
+				t.Errorf("APIError has status %d & body %q, want %d & %q", apiErr.StatusCode, apiErr.Body, tc.status, tc.body)
+			}
+			var vErr *mira.ValidationError
+			switch {
+			case errors.As(err, &vErr):
+				if !vErr.Has(tc.code) {
+					t.Errorf("ValidationError %v doesn't have code %s", vErr, tc.code)
+				}
+			case apiErr.Code != tc.code:
+				t.Errorf("APIError has code %q, want %q", apiErr.Code, tc.code)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package mira_test
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/ttgmpsn/mira"
@@ -153,7 +155,7 @@ func ExampleReddit() {
 	// If redditInstances is a global variable, you can now use it everywhere!
 }
 
-func ExampleReddit_StreamPosts() {
+func ExampleQueued_StreamPosts() {
 	// Initialize reddit instance like usually - see other examples.
 	reddit := mira.Init(mira.Credentials{})
 
@@ -175,3 +177,238 @@ func ExampleReddit_StreamPosts() {
 		}
 	}()
 }
+
+func ExampleQueued_StreamInboxWithOptions() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Stream mentions & mark them as read, so they aren't delivered again after a restart
+	stream, err := reddit.Me().StreamInboxWithOptions(mira.InboxStreamOptions{
+		Where:    mira.InboxMentions,
+		MarkRead: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for m := range stream.C {
+		if _, err := reddit.Message(string(m.Name)).Reply("You called?"); err != nil {
+			fmt.Println("couldn't answer:", err)
+		}
+	}
+}
+
+func ExampleQueued_StreamComments() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Watch the comments of multiple redditors at once
+	stream, err := reddit.Redditor("spammer1", "spammer2").StreamComments()
+	if err != nil {
+		panic(err)
+	}
+
+	for s := range stream.C {
+		fmt.Println(s.GetAuthor(), "commented:", s.GetURL())
+	}
+}
+
+func ExampleQueued_StreamModLogWithOptions() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Get alerted about bans & removals in two subreddits
+	stream, err := reddit.Subreddit("pics", "funny").StreamModLogWithOptions(mira.ModLogStreamOptions{
+		Actions: []miramodels.ModActionType{miramodels.ModActionBanUser, miramodels.ModActionRemoveLink},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for a := range stream.C {
+		fmt.Printf("%s: %s did %s to %s\n", a.Subreddit, a.Mod, a.Action, a.TargetAuthor)
+	}
+}
+
+func ExampleQueued_StreamModMail() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Forward modmail of all subreddits you moderate to a chat
+	stream, err := reddit.Me().StreamModMail()
+	if err != nil {
+		panic(err)
+	}
+
+	for conv := range stream.C {
+		if conv.NewConversation {
+			fmt.Printf("new modmail in r/%s: %s\n", conv.Conversation.Owner.DisplayName, conv.Conversation.Subject)
+		}
+		for _, msg := range conv.NewMessages {
+			fmt.Printf("[%s] %s: %s\n", conv.Conversation.Subject, msg.Author.Name, msg.BodyMarkdown)
+		}
+	}
+}
+
+func ExampleQueued_WithStreamOptions() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Poll every 10 seconds, adapting between 2 seconds on busy days & 1 minute on quiet ones
+	stream, err := reddit.Subreddit("pics").WithStreamOptions(mira.StreamOptions{
+		Interval:    10 * time.Second,
+		MinInterval: 2 * time.Second,
+		MaxInterval: time.Minute,
+	}).StreamComments()
+	if err != nil {
+		panic(err)
+	}
+
+	go func() {
+		for err := range stream.Errors {
+			fmt.Println("error while streaming:", err)
+		}
+	}()
+	for s := range stream.C {
+		fmt.Println("Received new item in stream:", s.GetID())
+	}
+	fmt.Println("Stream was closed")
+}
+
+func ExampleNewFileCheckpoints() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// After a restart, the stream first delivers everything posted while the bot was down
+	stream, err := reddit.Subreddit("pics").WithStreamOptions(mira.StreamOptions{
+		Checkpoints: mira.NewFileCheckpoints("checkpoints.json"),
+	}).StreamPosts()
+	if err != nil {
+		panic(err)
+	}
+
+	for s := range stream.C {
+		fmt.Println("Received new item in stream:", s.GetID())
+	}
+}
+
+func ExampleQueued_SubmitWithOptions() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Upload two images as a gallery, marked as spoiler
+	cat, err := os.Open("cat.png")
+	if err != nil {
+		panic(err)
+	}
+	defer cat.Close()
+	dog, err := os.Open("dog.jpg")
+	if err != nil {
+		panic(err)
+	}
+	defer dog.Close()
+
+	res, err := reddit.Subreddit("pics").SubmitWithOptions(mira.SubmitOptions{
+		Title: "My pets",
+		Images: []mira.MediaUpload{
+			{Name: "cat.png", Data: cat, Caption: "Tom"},
+			{Name: "dog.jpg", Data: dog, Caption: "Rex"},
+		},
+		Spoiler: true,
+	})
+	var verr *mira.ValidationError
+	if errors.As(err, &verr) && verr.Has("RATELIMIT") {
+		fmt.Println("Posting too fast:", verr)
+		return
+	} else if err != nil {
+		panic(err)
+	}
+	fmt.Println("Posted", res.Name, "to", res.URL)
+}
+
+func ExampleQueued_IterBanned() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+	sub := reddit.Subreddit("pics")
+
+	// Ban a spammer for a week
+	err := sub.BanWithOptions("spammer", mira.BanOptions{
+		Duration: 7,
+		Reason:   "Spam",
+		Note:     "posted the same link 20 times",
+		Message:  "Please read the rules before posting again.",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// List all temporary bans & about when they end
+	it := sub.IterBanned(mira.IteratorOptions{})
+	for it.Next() {
+		if b := it.Item(); !b.IsPermanent() {
+			fmt.Println(b.Name, "is banned until about", b.Expires.Format("2006-01-02"), "-", b.Note)
+		}
+	}
+	if err := it.Err(); err != nil {
+		panic(err)
+	}
+}
+
+func ExampleQueued_IterModMail() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+
+	// Answer all new modmail of two subreddits & archive it
+	it := reddit.Subreddit("pics", "funny").IterModMail(miramodels.ModmailNew, mira.IteratorOptions{})
+	for it.Next() {
+		conv := it.Item()
+		fmt.Println("answering", conv.Conversation.Participant.Name, "-", conv.Conversation.Subject)
+		if _, err := reddit.ReplyModMail(conv.Conversation.ID, "Thanks, we'll look into it!", true); err != nil {
+			panic(err)
+		}
+		if err := reddit.ArchiveModMail(conv.Conversation.ID); err != nil {
+			panic(err)
+		}
+	}
+	if err := it.Err(); err != nil {
+		panic(err)
+	}
+}
+
+func ExampleQueued_EditWikiWithOptions() {
+	// Initialize reddit instance like usually - see other examples.
+	reddit := mira.Init(mira.Credentials{})
+	sub := reddit.Subreddit("pics")
+
+	// Append a line to the wiki without overwriting edits made in the meantime
+	for {
+		wiki, err := sub.Wiki("banned-domains")
+		if err != nil {
+			panic(err)
+		}
+		err = sub.EditWikiWithOptions("banned-domains", mira.WikiEditOptions{
+			Content:  wiki.ContentMD + "\n* spam.example.com",
+			Reason:   "ban spam.example.com",
+			Previous: wiki.RevisionID,
+		})
+		var conflict *mira.WikiConflictError
+		if errors.As(err, &conflict) {
+			fmt.Println("page changed in revision", conflict.NewRevision, "- trying again")
+			continue
+		}
+		if err != nil {
+			panic(err)
+		}
+		break
+	}
+
+	// Show who edited the page
+	it := sub.IterWikiRevisions("banned-domains", mira.IteratorOptions{Max: 10})
+	for it.Next() {
+		rev := it.Item()
+		fmt.Println(rev.CreatedAt().Format("2006-01-02"), rev.GetAuthor(), rev.Reason)
+	}
+	if err := it.Err(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,104 @@
+package mira_test
+
+import (
+	"testing"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestInbox(t *testing.T) {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm := srv.AddMessage(&models.Message{Author: "alice", Subject: "hi", Body: "how are you?"})
+	// replies & mentions are sent as t1, but with the fields of a message
+	reply := srv.AddMessage(&models.Message{
+		Author:     "bob",
+		Body:       "I agree",
+		WasComment: true,
+		Type:       models.MessageTypeCommentReply,
+		New:        true,
+	})
+
+	msgs, err := reddit.Me().Messages("inbox", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want the reply & the private message", len(msgs))
+	}
+	if msgs[0].Name != reply.Name || msgs[0].Name.Type() != models.KComment || !msgs[0].WasComment || msgs[0].IsPrivate() {
+		t.Errorf("got %s (was comment: %t), want the reply %s", msgs[0].Name, msgs[0].WasComment, reply.Name)
+	}
+	if msgs[1].Name != pm.Name || !msgs[1].IsPrivate() {
+		t.Errorf("got %s (private: %t), want the private message %s", msgs[1].Name, msgs[1].IsPrivate(), pm.Name)
+	}
+}
+
+func TestMarkMessages(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		path string
+		new  bool
+		mark func(q mira.Queued) error
+	}{
+		{name: "read", path: "/api/read_message", new: false, mark: mira.Queued.MarkRead},
+		{name: "unread", path: "/api/unread_message", new: true, mark: mira.Queued.MarkUnread},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := miratest.NewServer()
+			defer srv.Close()
+			reddit, err := srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			pm := srv.AddMessage(&models.Message{Author: "alice", Subject: "hi", Body: "hi", New: !tc.new})
+			reply := srv.AddMessage(&models.Message{Author: "bob", Body: "hi", WasComment: true, New: !tc.new})
+
+			for _, m := range []*models.Message{pm, reply} {
+				if err := tc.mark(reddit.Message(string(m.Name))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			actions := srv.ActionsTo(tc.path)
+			if len(actions) != 2 || actions[0].Form.Get("id") != string(pm.Name) || actions[1].Form.Get("id") != string(reply.Name) {
+				t.Fatalf("got requests %+v, want one for %s & %s", actions, pm.Name, reply.Name)
+			}
+			for _, m := range srv.Messages() {
+				if m.New != tc.new {
+					t.Errorf("%s is new: %t, want %t", m.Name, m.New, tc.new)
+				}
+			}
+		})
+	}
+}
+
+func TestBlock(t *testing.T) {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm := srv.AddMessage(&models.Message{Author: "spammer", Subject: "buy now", Body: "cheap"})
+
+	if err := reddit.Message(string(pm.Name)).Block(); err != nil {
+		t.Fatal(err)
+	}
+	actions := srv.ActionsTo("/api/block")
+	if len(actions) != 1 || actions[0].Form.Get("id") != string(pm.Name) {
+		t.Fatalf("got requests %+v, want one for %s", actions, pm.Name)
+	}
+
+	if err := reddit.Message("t4_missing").Block(); err == nil {
+		t.Error("blocking the author of a missing message succeeded")
+	}
+	if err := reddit.Redditor("spammer").Block(); err == nil {
+		t.Error("blocking a redditor succeeded")
+	}
+}
@@ -0,0 +1,354 @@
+package mira
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// IteratorOptions limits how far an Iterator pages through a listing.
+// The zero value iterates until the listing ends.
+type IteratorOptions struct {
+	// Max is the total number of items to return. 0 means no limit.
+	Max int
+	// Since stops the iteration at the first item created before it. This is
+	// only useful for listings sorted by date, i.e. "new" or the mod log.
+	Since time.Time
+	// PageSize is the number of items requested per page, up to 100 (the default).
+	PageSize int
+}
+
+// Iterator pages through a reddit listing by following its "after" cursor.
+// Pages are only fetched when needed, and all requests count against the rate limit.
+//
+//	it := reddit.Subreddit("pics").IterPosts("new", "all", mira.IteratorOptions{Max: 500})
+//	for it.Next() {
+//		fmt.Println(it.Item().Title)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type Iterator[T models.RedditThing] struct {
+	ctx    context.Context
+	target string
+	params map[string]string
+	opts   IteratorOptions
+	// list requests a page & returns its items and the cursor of the next page ("" at the end).
+	list func(ctx context.Context, target string, params map[string]string) ([]T, string, error)
+
+	page  []T
+	item  T
+	after string
+	count int
+	done  bool
+	err   error
+}
+
+// NewIterator creates an Iterator for any listing endpoint, i.e. for endpoints mira doesn't offer
+// an Iter method for. target and params are passed to MiraRequestContext, the paging parameters
+// (limit, after) are added automatically. Items in the listing that are not of type T are skipped.
+func NewIterator[T models.RedditThing](ctx context.Context, c *Reddit, target string, params map[string]string, opts IteratorOptions) *Iterator[T] {
+	p := make(map[string]string, len(params)+2)
+	for k, v := range params {
+		p[k] = v
+	}
+	if opts.PageSize <= 0 || opts.PageSize > 100 {
+		opts.PageSize = 100
+	}
+	return &Iterator[T]{
+		ctx:    ctx,
+		target: target,
+		params: p,
+		opts:   opts,
+		list:   listingPage[T](c),
+	}
+}
+
+// listingPage requests a page of a Listing. Items that are not of type T are skipped.
+func listingPage[T models.RedditThing](c *Reddit) func(context.Context, string, map[string]string) ([]T, string, error) {
+	return func(ctx context.Context, target string, params map[string]string) ([]T, string, error) {
+		list, err := c.miraRequestListing(ctx, "GET", target, params)
+		if err != nil || len(list.Children) == 0 {
+			return nil, "", err
+		}
+		items := make([]T, 0, len(list.Children))
+		for _, child := range list.Children {
+			if v, ok := child.Data.(T); ok {
+				items = append(items, v)
+			}
+		}
+		return items, list.After, nil
+	}
+}
+
+// newUserListIterator creates an Iterator for the user lists of a subreddit (banned, muted, ...),
+// which reddit doesn't send as a Listing.
+func newUserListIterator(ctx context.Context, c *Reddit, target string, params map[string]string, opts IteratorOptions) *Iterator[*models.Relationship] {
+	it := NewIterator[*models.Relationship](ctx, c, target, params, opts)
+	it.list = func(ctx context.Context, target string, params map[string]string) ([]*models.Relationship, string, error) {
+		list, err := c.getUserList(ctx, target, params)
+		if err != nil || len(list.Children) == 0 {
+			return nil, "", err
+		}
+		return list.Children, list.After, nil
+	}
+	return it
+}
+
+// newModMailIterator creates an Iterator for modmail conversations, which reddit doesn't send as a Listing.
+func newModMailIterator(ctx context.Context, c *Reddit, target string, params map[string]string, opts IteratorOptions) *Iterator[*models.NewModmailConversation] {
+	it := NewIterator[*models.NewModmailConversation](ctx, c, target, params, opts)
+	it.list = c.getModMail
+	return it
+}
+
+// newWikiRevisionIterator creates an Iterator for the revisions of wiki pages, which reddit sends
+// in a Listing with items of no kind.
+func newWikiRevisionIterator(ctx context.Context, c *Reddit, sr string, target string, opts IteratorOptions) *Iterator[*models.WikiRevision] {
+	it := NewIterator[*models.WikiRevision](ctx, c, target, nil, opts)
+	it.list = func(ctx context.Context, target string, params map[string]string) ([]*models.WikiRevision, string, error) {
+		return c.getWikiRevisions(ctx, sr, target, params)
+	}
+	return it
+}
+
+// errIterator returns an Iterator that doesn't return any items, but err.
+func errIterator[T models.RedditThing](err error) *Iterator[T] {
+	return &Iterator[T]{done: true, err: err}
+}
+
+// Next advances the Iterator to the next item, which is then available through Item.
+// It returns false once the listing or a limit set in IteratorOptions is reached, or on errors.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil || (it.opts.Max > 0 && it.count >= it.opts.Max) {
+		return false
+	}
+	for len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.item, it.page = it.page[0], it.page[1:]
+	if !it.opts.Since.IsZero() && it.item.CreatedAt().Before(it.opts.Since) {
+		it.done, it.page = true, nil
+		return false
+	}
+	it.count++
+	return true
+}
+
+// Item returns the current item.
+func (it *Iterator[T]) Item() T { return it.item }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error { return it.err }
+
+// After returns the cursor of the next page, i.e. to continue iterating later.
+func (it *Iterator[T]) After() string { return it.after }
+
+// fetch requests the next page.
+func (it *Iterator[T]) fetch() error {
+	limit := it.opts.PageSize
+	if it.opts.Max > 0 && it.opts.Max-it.count < limit {
+		limit = it.opts.Max - it.count
+	}
+	it.params["limit"] = strconv.Itoa(limit)
+	if it.after != "" {
+		it.params["after"] = it.after
+	}
+	items, after, err := it.list(it.ctx, it.target, it.params)
+	if err != nil {
+		return err
+	}
+	it.page = append(it.page, items...)
+	it.after = after
+	if it.after == "" {
+		it.done = true
+	}
+	return nil
+}
+
+// IterPosts is like Posts, but returns an Iterator over all pages.
+// Valid objects: Subreddit, Redditor
+func (q Queued) IterPosts(sort string, tdur string, opts IteratorOptions) *Iterator[*models.Post] {
+	return q.IterPostsContext(context.Background(), sort, tdur, opts)
+}
+
+// IterPostsContext is like IterPosts, but with a context.
+func (q Queued) IterPostsContext(ctx context.Context, sort string, tdur string, opts IteratorOptions) *Iterator[*models.Post] {
+	switch q.kind {
+	case models.KSubreddit:
+		return NewIterator[*models.Post](ctx, q.Reddit, q.endpoints.OAuth+"/r/"+q.name+"/"+sort+".json", map[string]string{"t": tdur}, opts)
+	case models.KRedditor:
+		return NewIterator[*models.Post](ctx, q.Reddit, q.endpoints.OAuth+"/u/"+q.name+"/submitted/"+sort+".json", map[string]string{"t": tdur}, opts)
+	default:
+		return errIterator[*models.Post](fmt.Errorf("'%s' type does not have an option for posts", q.kind))
+	}
+}
+
+// IterComments is like Comments, but returns an Iterator over all pages.
+// Valid objects: Subreddit, Redditor
+func (q Queued) IterComments(sort string, tdur string, opts IteratorOptions) *Iterator[*models.Comment] {
+	return q.IterCommentsContext(context.Background(), sort, tdur, opts)
+}
+
+// IterCommentsContext is like IterComments, but with a context.
+func (q Queued) IterCommentsContext(ctx context.Context, sort string, tdur string, opts IteratorOptions) *Iterator[*models.Comment] {
+	params := map[string]string{"sort": sort, "t": tdur}
+	switch q.kind {
+	case models.KSubreddit:
+		return NewIterator[*models.Comment](ctx, q.Reddit, q.endpoints.OAuth+"/r/"+q.name+"/comments.json", params, opts)
+	case models.KRedditor:
+		return NewIterator[*models.Comment](ctx, q.Reddit, q.endpoints.OAuth+"/u/"+q.name+"/comments.json", params, opts)
+	default:
+		return errIterator[*models.Comment](fmt.Errorf("'%s' type does not have an option for comments", q.kind))
+	}
+}
+
+// IterSubmissions is like Submissions, but returns an Iterator over all pages.
+// Valid objects: Redditor
+func (q Queued) IterSubmissions(opts IteratorOptions) *Iterator[models.Submission] {
+	return q.IterSubmissionsContext(context.Background(), opts)
+}
+
+// IterSubmissionsContext is like IterSubmissions, but with a context.
+func (q Queued) IterSubmissionsContext(ctx context.Context, opts IteratorOptions) *Iterator[models.Submission] {
+	if q.kind != models.KRedditor {
+		return errIterator[models.Submission](fmt.Errorf("'%s' type does not have an option for submissions", q.kind))
+	}
+	return NewIterator[models.Submission](ctx, q.Reddit, q.endpoints.OAuth+"/u/"+q.name+".json", nil, opts)
+}
+
+// IterModQueue is like ModQueue, but returns an Iterator over all pages.
+// Valid objects: Subreddit
+func (q Queued) IterModQueue(opts IteratorOptions) *Iterator[models.Submission] {
+	return q.IterModQueueContext(context.Background(), opts)
+}
+
+// IterModQueueContext is like IterModQueue, but with a context.
+func (q Queued) IterModQueueContext(ctx context.Context, opts IteratorOptions) *Iterator[models.Submission] {
+	if q.kind != models.KSubreddit {
+		return errIterator[models.Submission](fmt.Errorf("'%s' type does not have an option for modqueue", q.kind))
+	}
+	return NewIterator[models.Submission](ctx, q.Reddit, q.endpoints.OAuth+"/r/"+q.name+"/about/modqueue.json", nil, opts)
+}
+
+// IterModLog is like ModLog, but returns an Iterator over all pages.
+// Valid objects: Subreddit
+func (q Queued) IterModLog(mod string, opts IteratorOptions) *Iterator[*models.ModAction] {
+	return q.IterModLogContext(context.Background(), mod, opts)
+}
+
+// IterModLogContext is like IterModLog, but with a context.
+func (q Queued) IterModLogContext(ctx context.Context, mod string, opts IteratorOptions) *Iterator[*models.ModAction] {
+	if q.kind != models.KSubreddit {
+		return errIterator[*models.ModAction](fmt.Errorf("'%s' type does not have an option for modlog", q.kind))
+	}
+	params := map[string]string{}
+	if mod != "" {
+		params["mod"] = mod
+	}
+	return NewIterator[*models.ModAction](ctx, q.Reddit, q.endpoints.OAuth+"/r/"+q.name+"/about/log.json", params, opts)
+}
+
+// IterMessages is like Messages, but returns an Iterator over all pages.
+// Valid objects: Me
+func (q Queued) IterMessages(where string, opts IteratorOptions) *Iterator[*models.Message] {
+	return q.IterMessagesContext(context.Background(), where, opts)
+}
+
+// IterMessagesContext is like IterMessages, but with a context.
+func (q Queued) IterMessagesContext(ctx context.Context, where string, opts IteratorOptions) *Iterator[*models.Message] {
+	if _, _, err := q.checkType("me"); err != nil {
+		return errIterator[*models.Message](err)
+	}
+	return NewIterator[*models.Message](ctx, q.Reddit, q.endpoints.OAuth+"/message/"+where, map[string]string{"mark": "false"}, opts)
+}
+
+// IterBanned returns an Iterator over the redditors banned from the queued object, newest first.
+// Valid objects: Subreddit
+func (q Queued) IterBanned(opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.IterBannedContext(context.Background(), opts)
+}
+
+// IterBannedContext is like IterBanned, but with a context.
+func (q Queued) IterBannedContext(ctx context.Context, opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.iterUserList(ctx, "banned", opts)
+}
+
+// IterMuted returns an Iterator over the redditors muted in the modmail of the queued object, newest first.
+// Valid objects: Subreddit
+func (q Queued) IterMuted(opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.IterMutedContext(context.Background(), opts)
+}
+
+// IterMutedContext is like IterMuted, but with a context.
+func (q Queued) IterMutedContext(ctx context.Context, opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.iterUserList(ctx, "muted", opts)
+}
+
+// IterWikiBanned returns an Iterator over the redditors banned from the wiki of the queued object, newest first.
+// Valid objects: Subreddit
+func (q Queued) IterWikiBanned(opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.IterWikiBannedContext(context.Background(), opts)
+}
+
+// IterWikiBannedContext is like IterWikiBanned, but with a context.
+func (q Queued) IterWikiBannedContext(ctx context.Context, opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.iterUserList(ctx, "wikibanned", opts)
+}
+
+// IterContributors returns an Iterator over the approved users of the queued object, newest first.
+// Valid objects: Subreddit
+func (q Queued) IterContributors(opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.IterContributorsContext(context.Background(), opts)
+}
+
+// IterContributorsContext is like IterContributors, but with a context.
+func (q Queued) IterContributorsContext(ctx context.Context, opts IteratorOptions) *Iterator[*models.Relationship] {
+	return q.iterUserList(ctx, "contributors", opts)
+}
+
+// IterModMail is like ModMail, but returns an Iterator over all pages. IteratorOptions.Since
+// stops at the first conversation that wasn't updated since.
+// Valid objects: Subreddit (multiple subreddits are allowed), Me (all subreddits you moderate)
+func (q Queued) IterModMail(filter models.ModmailFilter, opts IteratorOptions) *Iterator[*models.NewModmailConversation] {
+	return q.IterModMailContext(context.Background(), filter, opts)
+}
+
+// IterModMailContext is like IterModMail, but with a context.
+func (q Queued) IterModMailContext(ctx context.Context, filter models.ModmailFilter, opts IteratorOptions) *Iterator[*models.NewModmailConversation] {
+	params, err := q.modMailParams(filter)
+	if err != nil {
+		return errIterator[*models.NewModmailConversation](err)
+	}
+	return newModMailIterator(ctx, q.Reddit, q.endpoints.OAuth+"/api/mod/conversations", params, opts)
+}
+
+// IterWikiRevisions is like WikiRevisions, but returns an Iterator over all pages.
+// Valid objects: Subreddit
+func (q Queued) IterWikiRevisions(page string, opts IteratorOptions) *Iterator[*models.WikiRevision] {
+	return q.IterWikiRevisionsContext(context.Background(), page, opts)
+}
+
+// IterWikiRevisionsContext is like IterWikiRevisions, but with a context.
+func (q Queued) IterWikiRevisionsContext(ctx context.Context, page string, opts IteratorOptions) *Iterator[*models.WikiRevision] {
+	if q.kind != models.KSubreddit {
+		return errIterator[*models.WikiRevision](fmt.Errorf("'%s' type does not have an option for wiki revisions", q.kind))
+	}
+	return newWikiRevisionIterator(ctx, q.Reddit, q.name, wikiRevisionsTarget(q.Reddit, q.name, page), opts)
+}
+
+func (q Queued) iterUserList(ctx context.Context, where string, opts IteratorOptions) *Iterator[*models.Relationship] {
+	if q.kind != models.KSubreddit {
+		return errIterator[*models.Relationship](fmt.Errorf("'%s' type does not have an option for %s", q.kind, where))
+	}
+	return newUserListIterator(ctx, q.Reddit, q.endpoints.OAuth+"/r/"+q.name+"/about/"+where, nil, opts)
+}
@@ -0,0 +1,169 @@
+package mira_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// seedPosts adds n posts to r/test, one per second starting at base. They are returned oldest first.
+func seedPosts(srv *miratest.Server, n int, base time.Time) []*models.Post {
+	posts := make([]*models.Post, n)
+	for i := range posts {
+		posts[i] = srv.AddPost(&models.Post{Subreddit: "test", CreatedUTC: float64(base.Unix() + int64(i))})
+	}
+	return posts
+}
+
+func TestIterator(t *testing.T) {
+	const path = "/r/test/new.json"
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name   string
+		opts   mira.IteratorOptions
+		want   int
+		limits []string
+	}{
+		{name: "all", want: 250, limits: []string{"100", "100", "100"}},
+		{name: "max", opts: mira.IteratorOptions{Max: 150}, want: 150, limits: []string{"100", "50"}},
+		{name: "page size", opts: mira.IteratorOptions{PageSize: 60, Max: 130}, want: 130, limits: []string{"60", "60", "10"}},
+		{name: "since", opts: mira.IteratorOptions{Since: base.Add(130 * time.Second)}, want: 120, limits: []string{"100", "100"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := miratest.NewServer()
+			defer srv.Close()
+			posts := seedPosts(srv, 250, base)
+			reddit, err := srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			it := reddit.Subreddit("test").IterPosts("new", "all", tc.opts)
+			var got []*models.Post
+			for it.Next() {
+				got = append(got, it.Item())
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tc.want {
+				t.Fatalf("got %d posts, want %d", len(got), tc.want)
+			}
+			// newest first, without gaps or duplicates
+			for i, p := range got {
+				if want := posts[len(posts)-1-i]; p.Name != want.Name {
+					t.Fatalf("post %d is %s, want %s", i, p.Name, want.Name)
+				}
+			}
+			if it.Next() {
+				t.Error("Next returned true after the end")
+			}
+
+			reqs := srv.RequestsTo(path)
+			if len(reqs) != len(tc.limits) {
+				t.Fatalf("got %d requests, want %d", len(reqs), len(tc.limits))
+			}
+			for i, r := range reqs {
+				if r.Form.Get("limit") != tc.limits[i] {
+					t.Errorf("request %d has limit %s, want %s", i, r.Form.Get("limit"), tc.limits[i])
+				}
+				if i > 0 && r.Form.Get("after") == reqs[i-1].Form.Get("after") {
+					t.Errorf("request %d didn't advance the cursor", i)
+				}
+			}
+		})
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	const path = "/r/test/new.json"
+	srv := miratest.NewServer()
+	defer srv.Close()
+	seedPosts(srv, 150, time.Now().Add(-time.Hour))
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	it := reddit.Subreddit("test").IterPosts("new", "all", mira.IteratorOptions{})
+	n := 0
+	for it.Next() {
+		n++
+		if n == 100 {
+			// the first page has been fetched, fail the second one
+			srv.Fail(path, 1, http.StatusForbidden, "")
+		}
+	}
+	if n != 100 {
+		t.Errorf("got %d posts, want the 100 of the first page", n)
+	}
+	var fErr *mira.ForbiddenError
+	if !errors.As(it.Err(), &fErr) {
+		t.Fatalf("Err() = %v, want ForbiddenError", it.Err())
+	}
+	// the iterator stays stopped
+	if it.Next() || !errors.As(it.Err(), &fErr) {
+		t.Errorf("Next() continued after an error, Err() = %v", it.Err())
+	}
+	if reqs := srv.RequestsTo(path); len(reqs) != 2 {
+		t.Errorf("got %d requests, want 2", len(reqs))
+	}
+}
+
+func TestModLog(t *testing.T) {
+	const path = "/r/test/about/log.json"
+	srv, reddit := newTestServer(t)
+	srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "alice", Action: models.ModActionBanUser})
+	srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "bob", Action: models.ModActionRemoveLink})
+	sub := reddit.Subreddit("test")
+	modLog := func(mod string) ([]*models.ModAction, error) { return sub.ModLog(10, mod) }
+	iterModLog := func(mod string) ([]*models.ModAction, error) {
+		it := sub.IterModLog(mod, mira.IteratorOptions{})
+		var ret []*models.ModAction
+		for it.Next() {
+			ret = append(ret, it.Item())
+		}
+		return ret, it.Err()
+	}
+
+	for _, tc := range []struct {
+		name string
+		mod  string
+		list func(mod string) ([]*models.ModAction, error)
+	}{
+		{name: "ModLog", list: modLog},
+		{name: "ModLog filtered", mod: "alice", list: modLog},
+		{name: "IterModLog", list: iterModLog},
+		{name: "IterModLog filtered", mod: "alice", list: iterModLog},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			before := len(srv.RequestsTo(path))
+			actions, err := tc.list(tc.mod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 2
+			if tc.mod != "" {
+				want = 1
+			}
+			if len(actions) != want {
+				t.Errorf("got %d actions, want %d", len(actions), want)
+			}
+			// like the mod log stream, an empty filter isn't sent at all
+			for _, r := range srv.RequestsTo(path)[before:] {
+				if r.Form.Has("mod") != (tc.mod != "") || r.Form.Get("mod") != tc.mod {
+					t.Errorf("requested mod %q (sent: %v), want %q", r.Form.Get("mod"), r.Form.Has("mod"), tc.mod)
+				}
+			}
+		})
+	}
+	if _, err := reddit.Redditor("alice").ModLog(10, ""); err == nil {
+		t.Error("listing the mod log of a redditor succeeded")
+	}
+}
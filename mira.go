@@ -5,7 +5,6 @@ package mira
 // LoginAuth() or CodeAuth() afterwards, see the examples there.
 func Init(c Credentials) *Reddit {
 	instance := newOAuthSession(c)
-	instance.Chain = make(chan *chainVals, 32)
 	instance.SetDefault()
 	return instance
 }
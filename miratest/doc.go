@@ -0,0 +1,45 @@
+// Package miratest provides an in-memory fake of the reddit API to test bots built with mira
+// without network access.
+//
+// Start a Server, seed it with posts, comments & other data, and get a Reddit instance that
+// is logged in to it. Everything your bot does can be inspected afterwards:
+//
+//	srv := miratest.NewServer()
+//	defer srv.Close()
+//	srv.AddPost(&models.Post{Subreddit: "test", Title: "Hello"})
+//
+//	reddit, err := srv.Reddit()
+//	// ... run your bot with reddit ...
+//
+//	for _, a := range srv.Actions() {
+//		fmt.Println(a.Path, a.Form)
+//	}
+//
+// Actions only lists requests that changed something. Requests lists all of them, including
+// listings & their parameters.
+//
+// # Supported Endpoints
+//
+// The fake covers what mira calls: /api/v1/access_token, /api/v1/me, /api/info, /r/{sr}/{sort},
+// /r/{sr}/comments, /r/{sr}/about, /u/{user}/submitted/{sort}, /u/{user}/comments, /comments/{id},
+// /api/morechildren, /api/comment, /api/submit (and submit_gallery_post.json &
+// submit_poll_post.json), /api/media/asset.json, /api/vote, /api/save, /api/unsave, /api/hide,
+// /api/unhide, /api/marknsfw, /api/unmarknsfw, /api/spoiler, /api/unspoiler, /api/report,
+// /api/approve, /api/remove, /api/del, /r/{sr}/about/modqueue (and reports, spam, edited,
+// unmoderated, banned, muted, wikibanned, contributors & moderators), /r/{sr}/about/log,
+// /r/{sr}/api/friend, /r/{sr}/api/unfriend, /r/{sr}/api/setpermissions,
+// /r/{sr}/api/accept_moderator_invite, /api/leavemoderator, /r/{sr}/wiki/{page} (and pages,
+// revisions & settings), /r/{sr}/api/wiki/edit (and revert, hide & alloweditor),
+// /api/mod/conversations (and {id}, its actions, read, unread & unread/count), /message/{where},
+// /api/compose, /api/read_message, /api/unread_message, /api/read_all_messages and /api/block. All
+// other requests are answered with 404.
+//
+// Like reddit, /comments/{id} leaves out comments beyond its limit & depth parameters. They are
+// replaced by "more" placeholders, or "continue this thread" ones below the 10th level by default.
+//
+// # Errors and Rate Limits
+//
+// Use Fail, FailRetryAfter or Reject to make requests fail, and SetRateLimit to control the
+// X-Ratelimit headers sent to the client. Once the budget is used up, the Server answers with
+// 429 Too Many Requests until the period resets, just like reddit does.
+package miratest
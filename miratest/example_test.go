@@ -0,0 +1,186 @@
+package miratest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+	miramodels "github.com/ttgmpsn/mira/models"
+)
+
+func ExampleServer() {
+	srv := miratest.NewServer()
+	defer srv.Close()
+
+	// Seed the server with a post & a reported comment
+	post := srv.AddPost(&miramodels.Post{Subreddit: "test", Title: "Welcome!", Author: "spez"})
+	comment := srv.AddComment(&miramodels.Comment{
+		LinkID:      post.Name,
+		Author:      "troll",
+		Body:        "buy cheap stuff here",
+		NumReports:  1,
+		UserReports: []miramodels.UserReport{{Reason: "spam", Count: 1}},
+	})
+
+	reddit, err := srv.Reddit()
+	if err != nil {
+		panic(err)
+	}
+
+	// This would usually be your bot:
+	queue, err := reddit.Subreddit("test").ModQueue(100)
+	if err != nil {
+		panic(err)
+	}
+	for _, s := range queue {
+		if err := reddit.Comment(string(s.GetID())).Remove(true); err != nil {
+			panic(err)
+		}
+	}
+
+	// Check what the bot did
+	for _, a := range srv.Actions() {
+		fmt.Println(a.Path, a.Form.Get("id") == string(comment.Name), a.Form.Get("spam"))
+	}
+	fmt.Println("removed:", srv.Submission(comment.Name).IsRemoved())
+	fmt.Println("mod log:", srv.ModLog("test")[0].Action)
+	// Output:
+	// /api/remove true true
+	// removed: true
+	// mod log: spamcomment
+}
+
+// Errors can be injected to test how your bot handles them.
+func ExampleServer_Fail() {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		panic(err)
+	}
+
+	// reddit is down, but comes back before mira runs out of retries
+	srv.Fail("/r/test/new.json", 2, http.StatusServiceUnavailable, "")
+	posts, err := reddit.Subreddit("test").Posts("new", "all", 10)
+	fmt.Println(len(posts), err)
+
+	// reddit rejects a submission
+	srv.Reject("/api/submit", "SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr")
+	_, err = reddit.Subreddit("test").Submit("Hello", "World")
+	var vErr *mira.ValidationError
+	if errors.As(err, &vErr) {
+		fmt.Println(vErr.Has("SUBREDDIT_NOEXIST"), vErr.Errors[0].Field)
+	}
+	// Output:
+	// 0 <nil>
+	// true sr
+}
+
+// Comment threads are returned as a tree. Comments that don't fit into the first response are
+// loaded with additional requests.
+func ExampleServer_commentTree() {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	post := srv.AddPost(&miramodels.Post{Subreddit: "test", Title: "Discussion"})
+	parent := srv.AddComment(&miramodels.Comment{LinkID: post.Name, Body: "first"})
+	srv.AddComment(&miramodels.Comment{LinkID: post.Name, ParentID: parent.Name, Body: "reply"})
+	srv.AddComment(&miramodels.Comment{LinkID: post.Name, Body: "second"})
+
+	reddit, err := srv.Reddit()
+	if err != nil {
+		panic(err)
+	}
+	tree, err := reddit.Post(string(post.Name)).CommentTree(mira.CommentTreeOptions{Limit: 1, MaxRequests: -1})
+	if err != nil {
+		panic(err)
+	}
+	tree.Walk(func(c *miramodels.Comment, depth int) bool {
+		fmt.Println(depth, c.Body)
+		return true
+	})
+	fmt.Println("unloaded:", len(tree.More()))
+	// Output:
+	// 0 first
+	// 1 reply
+	// 0 second
+	// unloaded: 0
+}
+
+func ExampleServer_AddMessage() {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	srv.AddMessage(&miramodels.Message{Author: "alice", Subject: "hi", Body: "how are you?", New: true})
+	srv.AddMessage(&miramodels.Message{
+		Author:     "bob",
+		Body:       "/u/miratest look at this",
+		WasComment: true,
+		Type:       miramodels.MessageTypeUsernameMention,
+		New:        true,
+	})
+
+	reddit, err := srv.Reddit()
+	if err != nil {
+		panic(err)
+	}
+	unread, err := reddit.Me().ListUnreadMessages()
+	if err != nil {
+		panic(err)
+	}
+	for _, m := range unread {
+		fmt.Println(m.Author, m.IsPrivate())
+		if m.IsPrivate() {
+			if _, err := reddit.Message(string(m.Name)).Reply("great, thanks!"); err != nil {
+				panic(err)
+			}
+		}
+		if err := reddit.Message(string(m.Name)).MarkRead(); err != nil {
+			panic(err)
+		}
+	}
+
+	sent, err := reddit.Me().Messages(mira.InboxSent, 10)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(sent[0].Dest, sent[0].Subject)
+	// Output:
+	// bob false
+	// alice true
+	// alice re: hi
+}
+
+func ExampleServer_Uploads() {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		panic(err)
+	}
+
+	res, err := reddit.Subreddit("test").SubmitWithOptions(mira.SubmitOptions{
+		Title: "My pets",
+		Images: []mira.MediaUpload{
+			{Name: "cat.png", Data: strings.NewReader("meow")},
+			{Name: "dog.jpg", Data: strings.NewReader("woof")},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	for _, u := range srv.Uploads() {
+		fmt.Printf("%s %s %q\n", u.Name, u.MimeType, u.Data)
+	}
+
+	post := srv.Submission(res.Name).(*miramodels.Post)
+	for _, u := range post.MediaURLs() {
+		fmt.Println(strings.TrimPrefix(u, srv.URL))
+	}
+	// Output:
+	// cat.png image/png "meow"
+	// dog.jpg image/jpeg "woof"
+	// /media-upload/miratest/10001/cat.png
+	// /media-upload/miratest/10002/dog.jpg
+}
@@ -0,0 +1,1736 @@
+package miratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/access_token", s.handleAccessToken)
+	mux.HandleFunc("GET /api/v1/me", s.handleMe)
+	mux.HandleFunc("GET /api/info", s.handleInfo)
+	mux.HandleFunc("GET /api/info.json", s.handleInfo)
+	mux.HandleFunc("GET /r/{sr}/about", s.handleAbout)
+	mux.HandleFunc("GET /r/{sr}/{sort}", s.handleListing)
+	mux.HandleFunc("GET /r/{sr}/about/{where}", s.handleAboutListing)
+	mux.HandleFunc("POST /r/{sr}/api/friend", s.handleFriend)
+	mux.HandleFunc("POST /r/{sr}/api/unfriend", s.handleUnfriend)
+	mux.HandleFunc("POST /r/{sr}/api/setpermissions", s.handleSetPermissions)
+	mux.HandleFunc("POST /r/{sr}/api/accept_moderator_invite", s.handleAcceptModeratorInvite)
+	mux.HandleFunc("POST /api/leavemoderator", s.handleLeaveModerator)
+	mux.HandleFunc("GET /u/{user}/submitted/{sort}", s.handleUserListing(models.KPost))
+	mux.HandleFunc("GET /u/{user}/comments", s.handleUserListing(models.KComment))
+	mux.HandleFunc("GET /u/{user}/comments.json", s.handleUserListing(models.KComment))
+	mux.HandleFunc("GET /comments/{id}", s.handlePostComments)
+	mux.HandleFunc("GET /api/morechildren", s.handleMoreChildren)
+	mux.HandleFunc("POST /api/comment", s.handleComment)
+	mux.HandleFunc("POST /api/submit", s.handleSubmit)
+	mux.HandleFunc("POST /api/submit_gallery_post.json", s.handleSubmitGallery)
+	mux.HandleFunc("POST /api/submit_poll_post.json", s.handleSubmitPoll)
+	mux.HandleFunc("POST /api/media/asset.json", s.handleMediaAsset)
+	mux.HandleFunc("POST "+uploadPath, s.handleMediaUpload)
+	mux.HandleFunc("POST /api/compose", s.handleCompose)
+	mux.HandleFunc("GET /message/{where}", s.handleMessages)
+	mux.HandleFunc("POST /api/read_message", s.handleMarkMessages(false))
+	mux.HandleFunc("POST /api/unread_message", s.handleMarkMessages(true))
+	mux.HandleFunc("POST /api/read_all_messages", s.handleReadAllMessages)
+	mux.HandleFunc("POST /api/block", s.handleBlock)
+	mux.HandleFunc("POST /api/vote", s.handleVote)
+	mux.HandleFunc("POST /api/save", s.handleUserAction(func(sub models.Submission) { setSaved(sub, true) }))
+	mux.HandleFunc("POST /api/unsave", s.handleUserAction(func(sub models.Submission) { setSaved(sub, false) }))
+	mux.HandleFunc("POST /api/hide", s.handlePostAction(func(p *models.Post) { p.Hidden = true }))
+	mux.HandleFunc("POST /api/unhide", s.handlePostAction(func(p *models.Post) { p.Hidden = false }))
+	mux.HandleFunc("POST /api/marknsfw", s.handlePostAction(func(p *models.Post) { p.Over18 = true }))
+	mux.HandleFunc("POST /api/unmarknsfw", s.handlePostAction(func(p *models.Post) { p.Over18 = false }))
+	mux.HandleFunc("POST /api/spoiler", s.handlePostAction(func(p *models.Post) { p.Spoiler = true }))
+	mux.HandleFunc("POST /api/unspoiler", s.handlePostAction(func(p *models.Post) { p.Spoiler = false }))
+	mux.HandleFunc("POST /api/report", s.handleReport)
+	mux.HandleFunc("POST /api/approve", s.handleApprove)
+	mux.HandleFunc("POST /api/remove", s.handleRemove)
+	mux.HandleFunc("POST /api/del", s.handleDelete)
+	mux.HandleFunc("GET /r/{sr}/wiki/{page...}", s.handleWiki)
+	mux.HandleFunc("GET /r/{sr}/wiki/pages", s.handleWikiPages)
+	mux.HandleFunc("GET /r/{sr}/wiki/revisions", s.handleWikiRevisions)
+	mux.HandleFunc("GET /r/{sr}/wiki/revisions/{page...}", s.handleWikiRevisions)
+	mux.HandleFunc("GET /r/{sr}/wiki/settings/{page...}", s.handleWikiSettings)
+	mux.HandleFunc("POST /r/{sr}/wiki/settings/{page...}", s.handleWikiSettings)
+	mux.HandleFunc("POST /r/{sr}/api/wiki/edit", s.handleWikiEdit)
+	mux.HandleFunc("POST /r/{sr}/api/wiki/revert", s.handleWikiRevert)
+	mux.HandleFunc("POST /r/{sr}/api/wiki/hide", s.handleWikiHide)
+	mux.HandleFunc("POST /r/{sr}/api/wiki/alloweditor/{act}", s.handleWikiEditor)
+	mux.HandleFunc("GET /api/mod/conversations", s.handleModmailList)
+	mux.HandleFunc("POST /api/mod/conversations", s.handleModmailCreate)
+	mux.HandleFunc("GET /api/mod/conversations/{id}", s.handleModmail)
+	mux.HandleFunc("POST /api/mod/conversations/{id}", s.handleModmailReply)
+	mux.HandleFunc("POST /api/mod/conversations/{id}/{action}", s.handleModmailAction)
+	mux.HandleFunc("DELETE /api/mod/conversations/{id}/highlight", s.handleModmailAction)
+	mux.HandleFunc("POST /api/mod/conversations/read", s.handleModmailRead(false))
+	mux.HandleFunc("POST /api/mod/conversations/unread", s.handleModmailRead(true))
+	mux.HandleFunc("GET /api/mod/conversations/unread/count", s.handleModmailUnreadCount)
+	return s.middleware(mux)
+}
+
+// middleware records requests & actions, checks authentication, applies the rate limit and injects faults.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := parseForm(r); err != nil {
+			writeError(w, http.StatusBadRequest)
+			return
+		}
+		tokenRequest := r.URL.Path == "/api/v1/access_token"
+		// uploads go to reddits storage, not the API
+		upload := r.URL.Path == uploadPath
+
+		s.mu.Lock()
+		if !tokenRequest && !upload {
+			s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Form: r.Form, Time: time.Now()})
+		}
+		if r.Method != http.MethodGet && !tokenRequest && !upload {
+			s.actions = append(s.actions, Action{Method: r.Method, Path: r.URL.Path, Form: r.PostForm})
+		}
+		f := s.popFault(r.URL.Path)
+		var limited bool
+		if !tokenRequest && !upload {
+			limited = s.countRequest(w.Header())
+		}
+		s.mu.Unlock()
+
+		switch {
+		case !tokenRequest && !upload && r.Header.Get("Authorization") != "Bearer "+Token:
+			writeError(w, http.StatusUnauthorized)
+		case f != nil:
+			for k, v := range f.header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(f.status)
+			fmt.Fprint(w, f.body)
+		case limited:
+			writeError(w, http.StatusTooManyRequests)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// parseForm parses the form of r. JSON bodies are parsed into the form as well, so
+// they are handled & recorded like forms: nested values are kept as JSON.
+func parseForm(r *http.Request) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	if r.Header.Get("Content-Type") != "application/json" {
+		return nil
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return err
+	}
+	formValue := func(v interface{}) string {
+		if str, ok := v.(string); ok {
+			return str
+		}
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	for k, v := range body {
+		if list, ok := v.([]interface{}); ok {
+			for _, item := range list {
+				r.PostForm.Add(k, formValue(item))
+				r.Form.Add(k, formValue(item))
+			}
+			continue
+		}
+		r.PostForm.Set(k, formValue(v))
+		r.Form.Set(k, formValue(v))
+	}
+	return nil
+}
+
+// popFault returns the next injected fault for path, if any. s.mu must be held.
+func (s *Server) popFault(path string) *fault {
+	for i, f := range s.faults {
+		if f.path != "" && f.path != path {
+			continue
+		}
+		f.n--
+		if f.n <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// countRequest counts a request against the rate limit, sets the X-Ratelimit headers
+// and returns true if the budget is used up. s.mu must be held.
+func (s *Server) countRequest(h http.Header) bool {
+	now := time.Now()
+	if !now.Before(s.reset) {
+		s.used = 0
+		s.reset = now.Add(s.limitDur)
+	}
+	limited := s.used >= s.limit
+	if !limited {
+		s.used++
+	}
+	h.Set("X-Ratelimit-Remaining", strconv.FormatFloat(float64(s.limit-s.used), 'f', 1, 64))
+	h.Set("X-Ratelimit-Used", strconv.Itoa(s.used))
+	h.Set("X-Ratelimit-Reset", strconv.Itoa(int(s.reset.Sub(now).Seconds()+0.5)))
+	if limited {
+		h.Set("Retry-After", strconv.Itoa(int(s.reset.Sub(now).Seconds()+0.5)))
+	}
+	return limited
+}
+
+func (s *Server) handleAccessToken(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"access_token":  Token,
+		"token_type":    "bearer",
+		"expires_in":    3600,
+		"refresh_token": "miratest-refresh-token",
+		"scope":         "*",
+	})
+}
+
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &models.Me{ID: "miratest", Name: "miratest"})
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	children := []models.Submission{}
+	for _, id := range strings.Split(r.Form.Get("id"), ",") {
+		if sub, ok := s.things[models.RedditID(id)]; ok {
+			children = append(children, sub)
+		}
+	}
+	writeJSON(w, listing(children, "", ""))
+}
+
+func (s *Server) handleAbout(w http.ResponseWriter, r *http.Request) {
+	sr := r.PathValue("sr")
+	writeJSON(w, models.RedditElement{
+		Kind: models.KSubreddit,
+		Data: &models.Subreddit{
+			DisplayName: sr,
+			Name:        models.RedditID("t5_" + strings.ToLower(sr)),
+			URL:         "/r/" + sr + "/",
+		},
+	})
+}
+
+func (s *Server) handleListing(w http.ResponseWriter, r *http.Request) {
+	sort := strings.TrimSuffix(r.PathValue("sort"), ".json")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var items []models.Submission
+	if sort == "comments" {
+		items = s.list(r.PathValue("sr"), isKind(models.KComment))
+	} else {
+		items = s.list(r.PathValue("sr"), isKind(models.KPost))
+	}
+	writePage(w, r, items)
+}
+
+func (s *Server) handleAboutListing(w http.ResponseWriter, r *http.Request) {
+	where := strings.TrimSuffix(r.PathValue("where"), ".json")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch where {
+	case "modqueue":
+		writePage(w, r, s.list(r.PathValue("sr"), inModQueue))
+	case "reports":
+		writePage(w, r, s.list(r.PathValue("sr"), func(sub models.Submission) bool {
+			return sub.GetReports().Num > 0 && !sub.IsRemoved()
+		}))
+	case "spam":
+		writePage(w, r, s.list(r.PathValue("sr"), models.Submission.IsRemoved))
+	case "edited":
+		writePage(w, r, s.list(r.PathValue("sr"), models.Submission.IsEdited))
+	case "unmoderated":
+		writePage(w, r, s.list(r.PathValue("sr"), func(sub models.Submission) bool {
+			return sub.GetID().Type() == models.KPost && !sub.IsApproved() && !sub.IsRemoved()
+		}))
+	case "log":
+		s.handleModLog(w, r)
+	case "banned", "muted", "wikibanned":
+		s.writeUserList(w, r, where)
+	case "contributors", "moderators":
+		s.writeUserList(w, r, strings.TrimSuffix(where, "s"))
+	default:
+		writeError(w, http.StatusNotFound)
+	}
+}
+
+// writeUserList writes a user list of a subreddit. s.mu must be held.
+func (s *Server) writeUserList(w http.ResponseWriter, r *http.Request, listType string) {
+	children := []models.RedditElement{}
+	for _, rel := range s.users[r.PathValue("sr")+"/"+listType] {
+		if user := r.Form.Get("user"); user == "" || strings.EqualFold(user, rel.Name) {
+			children = append(children, models.RedditElement{Data: rel})
+		}
+	}
+	children, before, after := paginate(r, children)
+	list := models.UserList{Children: []*models.Relationship{}, Before: before, After: after}
+	for _, c := range children {
+		list.Children = append(list.Children, c.Data.(*models.Relationship))
+	}
+	writeJSON(w, map[string]interface{}{"kind": "UserList", "data": list})
+}
+
+// friendActions are the mod log entries of adding & removing redditors to user lists.
+var friendActions = map[string][2]models.ModActionType{
+	"banned":           {models.ModActionBanUser, models.ModActionUnbanUser},
+	"muted":            {models.ModActionMuteUser, models.ModActionUnmuteUser},
+	"wikibanned":       {models.ModActionWikiBanned, models.ModActionWikiUnbanned},
+	"contributor":      {models.ModActionAddContributor, models.ModActionRemoveContributor},
+	"moderator":        {models.ModActionAddModerator, models.ModActionRemoveModerator},
+	"moderator_invite": {models.ModActionInviteModerator, models.ModActionUninviteModerator},
+}
+
+func (s *Server) handleFriend(w http.ResponseWriter, r *http.Request) {
+	listType, name := r.Form.Get("type"), r.Form.Get("name")
+	actions, ok := friendActions[listType]
+	if !ok {
+		writeJSONErrors(w, "INVALID_OPTION", "that is not a valid type", "type")
+		return
+	}
+	if name == "" {
+		writeJSONErrors(w, "USER_REQUIRED", "please enter a username", "name")
+		return
+	}
+	rel := &models.Relationship{Name: name, Note: r.Form.Get("note")}
+	var details string
+	if listType == "banned" {
+		details = "permanent"
+	}
+	if d := r.Form.Get("duration"); d != "" {
+		days, err := strconv.Atoi(d)
+		if err != nil || days < 1 || days > 999 {
+			writeJSONErrors(w, "BAD_NUMBER", "that number isn't in the right range (1 to 999)", "duration")
+			return
+		}
+		rel.DaysLeft = &days
+		details = d + " days"
+	}
+	if listType == "moderator" || listType == "moderator_invite" {
+		rel.ModPermissions = parsePermissions(r.Form.Get("permissions"))
+	}
+	sr := r.PathValue("sr")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addRelationship(sr, listType, rel)
+	s.addModAction(&models.ModAction{
+		Action:       actions[0],
+		Mod:          "miratest",
+		Subreddit:    sr,
+		TargetAuthor: name,
+		Details:      details,
+		Description:  rel.Note,
+	})
+	writeJSON(w, map[string]interface{}{"json": map[string]interface{}{"errors": []interface{}{}}})
+}
+
+func (s *Server) handleUnfriend(w http.ResponseWriter, r *http.Request) {
+	listType, name := r.Form.Get("type"), r.Form.Get("name")
+	actions, ok := friendActions[listType]
+	if !ok {
+		writeJSONErrors(w, "INVALID_OPTION", "that is not a valid type", "type")
+		return
+	}
+	sr := r.PathValue("sr")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.removeRelationship(sr, listType, name) {
+		s.addModAction(&models.ModAction{
+			Action:       actions[1],
+			Mod:          "miratest",
+			Subreddit:    sr,
+			TargetAuthor: name,
+		})
+	}
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) handleSetPermissions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sr := r.PathValue("sr")
+	for _, rel := range s.users[sr+"/"+r.Form.Get("type")] {
+		if strings.EqualFold(rel.Name, r.Form.Get("name")) {
+			rel.ModPermissions = parsePermissions(r.Form.Get("permissions"))
+			s.addModAction(&models.ModAction{
+				Action:       models.ModActionSetPermissions,
+				Mod:          "miratest",
+				Subreddit:    sr,
+				TargetAuthor: rel.Name,
+				Details:      r.Form.Get("permissions"),
+			})
+			writeJSON(w, map[string]interface{}{"json": map[string]interface{}{"errors": []interface{}{}}})
+			return
+		}
+	}
+	writeJSONErrors(w, "USER_DOESNT_EXIST", "that user doesn't exist", "name")
+}
+
+func (s *Server) handleAcceptModeratorInvite(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sr := r.PathValue("sr")
+	for _, rel := range s.users[sr+"/moderator_invite"] {
+		if rel.Name == "miratest" {
+			perms := rel.ModPermissions
+			if len(perms) == 0 {
+				perms = []models.ModPermission{models.ModPermAll}
+			}
+			s.removeRelationship(sr, "moderator_invite", rel.Name)
+			s.addRelationship(sr, "moderator", &models.Relationship{Name: rel.Name, ModPermissions: perms})
+			s.addModAction(&models.ModAction{
+				Action:       models.ModActionAcceptModeratorInvite,
+				Mod:          "miratest",
+				Subreddit:    sr,
+				TargetAuthor: rel.Name,
+			})
+			writeJSON(w, map[string]interface{}{"json": map[string]interface{}{"errors": []interface{}{}}})
+			return
+		}
+	}
+	writeJSONErrors(w, "NO_INVITE_FOUND", "there is no pending invite for that subreddit", "")
+}
+
+func (s *Server) handleLeaveModerator(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// subreddit IDs of the fake are t5_ followed by the lower case name, see handleAbout
+	for key := range s.users {
+		sr, listType, _ := strings.Cut(key, "/")
+		if listType == "moderator" && r.Form.Get("id") == "t5_"+strings.ToLower(sr) {
+			if s.removeRelationship(sr, listType, "miratest") {
+				s.addModAction(&models.ModAction{
+					Action:       models.ModActionRemoveModerator,
+					Mod:          "miratest",
+					Subreddit:    sr,
+					TargetAuthor: "miratest",
+				})
+			}
+		}
+	}
+	writeJSON(w, map[string]interface{}{})
+}
+
+// parsePermissions reads the permissions of a moderator, i.e. "-all,+posts,+wiki".
+func parsePermissions(perms string) []models.ModPermission {
+	ret := []models.ModPermission{}
+	for _, p := range strings.Split(perms, ",") {
+		if strings.HasPrefix(p, "+") {
+			ret = append(ret, models.ModPermission(p[1:]))
+		}
+	}
+	if len(ret) == 0 {
+		ret = append(ret, models.ModPermAll)
+	}
+	return ret
+}
+
+// handleUserListing lists the posts or comments of a redditor, newest first.
+func (s *Server) handleUserListing(kind models.RedditKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.PathValue("user")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writePage(w, r, s.list("all", func(sub models.Submission) bool {
+			return sub.GetID().Type() == kind && strings.EqualFold(sub.GetAuthor(), user)
+		}))
+	}
+}
+
+// inModQueue tells you if a submission has reports & hasn't been handled yet.
+func inModQueue(sub models.Submission) bool {
+	return sub.GetReports().Num > 0 && !sub.IsApproved() && !sub.IsRemoved()
+}
+
+func isKind(kind models.RedditKind) func(models.Submission) bool {
+	return func(sub models.Submission) bool { return sub.GetID().Type() == kind }
+}
+
+// handleModLog writes the mod log of a subreddit. s.mu must be held.
+func (s *Server) handleModLog(w http.ResponseWriter, r *http.Request) {
+	subs := map[string]bool{}
+	for _, name := range strings.Split(strings.ToLower(r.PathValue("sr")), "+") {
+		subs[name] = true
+	}
+	mods := map[string]bool{}
+	if mod := r.Form.Get("mod"); mod != "" {
+		for _, name := range strings.Split(strings.ToLower(mod), ",") {
+			mods[name] = true
+		}
+	}
+	action := models.ModActionType(r.Form.Get("type"))
+	children := []models.RedditElement{}
+	for i := len(s.modlog) - 1; i >= 0; i-- {
+		a := s.modlog[i]
+		if !subs[strings.ToLower(a.Subreddit)] || (len(mods) > 0 && !mods[strings.ToLower(a.Mod)]) || (action != "" && a.Action != action) {
+			continue
+		}
+		children = append(children, models.RedditElement{Kind: models.KModAction, Data: a})
+	}
+	children, before, after := paginate(r, children)
+	writeJSON(w, listingResponse(children, before, after))
+}
+
+func (s *Server) handlePostComments(w http.ResponseWriter, r *http.Request) {
+	id := models.RedditID("t3_" + strings.TrimSuffix(r.PathValue("id"), ".json"))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	post, ok := s.things[id]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	limit, err := strconv.Atoi(r.Form.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 200
+	}
+	depth, err := strconv.Atoi(r.Form.Get("depth"))
+	if err != nil || depth <= 0 {
+		depth = defaultCommentDepth
+	}
+
+	children := s.commentChildren(id)
+	var replies models.Replies
+	if focus := r.Form.Get("comment"); focus != "" {
+		if c, ok := s.things[models.RedditID("t1_"+focus)].(*models.Comment); ok && c.LinkID == id {
+			cp := *c
+			cp.Replies = commentReplies(children, cp.Name, &limit, depth-1)
+			replies.Comments = []*models.Comment{&cp}
+		}
+	} else {
+		replies = commentReplies(children, id, &limit, depth)
+	}
+
+	comments := make([]models.RedditElement, 0, replies.Len())
+	for _, c := range replies.Comments {
+		comments = append(comments, models.RedditElement{Kind: models.KComment, Data: c})
+	}
+	for _, m := range replies.More {
+		comments = append(comments, models.RedditElement{Kind: models.KMore, Data: m})
+	}
+	writeJSON(w, []interface{}{
+		listing([]models.Submission{post}, "", ""),
+		listingResponse(comments, "", ""),
+	})
+}
+
+// commentChildren returns all comments of a post, grouped by their parent. s.mu must be held.
+func (s *Server) commentChildren(postID models.RedditID) map[models.RedditID][]*models.Comment {
+	children := map[models.RedditID][]*models.Comment{}
+	for _, cid := range s.order {
+		if c, ok := s.things[cid].(*models.Comment); ok && c.LinkID == postID {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		}
+	}
+	return children
+}
+
+// defaultCommentDepth is the number of levels of a comment tree reddit sends without a depth parameter.
+const defaultCommentDepth = 10
+
+// commentReplies builds the reply tree below parent from copies of the comments, with up to
+// *budget comments and depth levels. Comments exceeding the budget are replaced by a "more"
+// placeholder, and replies below the last level by a "continue this thread" placeholder.
+func commentReplies(children map[models.RedditID][]*models.Comment, parent models.RedditID, budget *int, depth int) models.Replies {
+	replies := models.Replies{}
+	if depth <= 0 {
+		if len(children[parent]) > 0 {
+			replies.More = []*models.More{{Name: "t1__", ID: "_", ParentID: parent}}
+		}
+		return replies
+	}
+	for i, c := range children[parent] {
+		if *budget <= 0 {
+			more := &models.More{ParentID: parent}
+			for _, left := range children[parent][i:] {
+				more.Children = append(more.Children, descendants(children, left)...)
+			}
+			more.Count = len(more.Children)
+			more.ID = more.Children[0]
+			more.Name = models.RedditID("t1_" + more.ID)
+			replies.More = append(replies.More, more)
+			break
+		}
+		*budget--
+		cp := *c
+		cp.Replies = commentReplies(children, cp.Name, budget, depth-1)
+		replies.Comments = append(replies.Comments, &cp)
+	}
+	return replies
+}
+
+// descendants returns the IDs of c and all replies below it, depth first.
+func descendants(children map[models.RedditID][]*models.Comment, c *models.Comment) []string {
+	ret := []string{c.ID}
+	for _, reply := range children[c.Name] {
+		ret = append(ret, descendants(children, reply)...)
+	}
+	return ret
+}
+
+func (s *Server) handleMoreChildren(w http.ResponseWriter, r *http.Request) {
+	linkID := models.RedditID(r.Form.Get("link_id"))
+	wanted := map[string]bool{}
+	for _, id := range strings.Split(r.Form.Get("children"), ",") {
+		wanted[id] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	things := []models.RedditElement{}
+	children := s.commentChildren(linkID)
+	var walk func(parent models.RedditID)
+	walk = func(parent models.RedditID) {
+		for _, c := range children[parent] {
+			if wanted[c.ID] {
+				cp := *c
+				cp.Replies = models.Replies{}
+				things = append(things, models.RedditElement{Kind: models.KComment, Data: &cp})
+			}
+			walk(c.Name)
+		}
+	}
+	walk(linkID)
+	writeJSON(w, map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": []interface{}{},
+			"data": map[string]interface{}{
+				"things": things,
+			},
+		},
+	})
+}
+
+func (s *Server) handleComment(w http.ResponseWriter, r *http.Request) {
+	parentID := models.RedditID(r.Form.Get("thing_id"))
+	if parentID.Type() == models.KMessage {
+		s.handleMessageReply(w, r, parentID)
+		return
+	}
+	s.mu.Lock()
+	parent, ok := s.things[parentID]
+	s.mu.Unlock()
+	if !ok {
+		writeJSONErrors(w, "NO_THING_ID", "that thing doesn't exist", "parent")
+		return
+	}
+	if r.Form.Get("text") == "" {
+		writeJSONErrors(w, "NO_TEXT", "we need something here", "text")
+		return
+	}
+	linkID := parentID
+	if c, ok := parent.(*models.Comment); ok {
+		linkID = c.LinkID
+	}
+	c := s.AddComment(&models.Comment{
+		Author:         "miratest",
+		AuthorFullname: "t2_miratest",
+		Body:           r.Form.Get("text"),
+		LinkID:         linkID,
+		ParentID:       parentID,
+		Subreddit:      parent.GetSubreddit(),
+		SubredditID:    parent.GetSubredditID(),
+	})
+	writeJSON(w, map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": []interface{}{},
+			"data": map[string]interface{}{
+				"things": []models.RedditElement{{Kind: models.KComment, Data: c}},
+			},
+		},
+	})
+}
+
+// handleMessageReply answers a private message.
+func (s *Server) handleMessageReply(w http.ResponseWriter, r *http.Request, parentID models.RedditID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parent := s.message(parentID)
+	if parent == nil {
+		writeJSONErrors(w, "NO_THING_ID", "that thing doesn't exist", "parent")
+		return
+	}
+	if r.Form.Get("text") == "" {
+		writeJSONErrors(w, "NO_TEXT", "we need something here", "text")
+		return
+	}
+	first := parent.FirstMessageName
+	if first == "" {
+		first = parent.Name
+	}
+	dest := parent.Author
+	if dest == "miratest" {
+		dest = parent.Dest
+	}
+	m := &models.Message{
+		Author:           "miratest",
+		AuthorFullname:   "t2_miratest",
+		Dest:             dest,
+		Subject:          "re: " + strings.TrimPrefix(parent.Subject, "re: "),
+		Body:             r.Form.Get("text"),
+		ParentID:         parent.Name,
+		FirstMessageName: first,
+	}
+	s.addMessage(m)
+	writeJSON(w, map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": []interface{}{},
+			"data": map[string]interface{}{
+				"things": []models.RedditElement{{Kind: models.KMessage, Data: m}},
+			},
+		},
+	})
+}
+
+func (s *Server) handleCompose(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Form.Get("to") == "":
+		writeJSONErrors(w, "NO_USER", "please enter a username", "to")
+		return
+	case r.Form.Get("subject") == "":
+		writeJSONErrors(w, "NO_SUBJECT", "please enter a subject", "subject")
+		return
+	case r.Form.Get("text") == "":
+		writeJSONErrors(w, "NO_TEXT", "we need something here", "text")
+		return
+	}
+	s.mu.Lock()
+	s.addMessage(&models.Message{
+		Author:         "miratest",
+		AuthorFullname: "t2_miratest",
+		Dest:           r.Form.Get("to"),
+		Subject:        r.Form.Get("subject"),
+		Body:           r.Form.Get("text"),
+	})
+	s.mu.Unlock()
+	writeJSON(w, map[string]interface{}{"json": map[string]interface{}{"errors": []interface{}{}}})
+}
+
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	var filter func(m *models.Message) bool
+	received := func(m *models.Message) bool { return m.Author != "miratest" }
+	switch strings.TrimSuffix(r.PathValue("where"), ".json") {
+	case "inbox":
+		filter = received
+	case "unread":
+		filter = func(m *models.Message) bool { return received(m) && m.New }
+	case "sent":
+		filter = func(m *models.Message) bool { return !received(m) }
+	case "messages":
+		filter = func(m *models.Message) bool { return received(m) && !m.WasComment }
+	case "mentions":
+		filter = func(m *models.Message) bool { return m.Type == models.MessageTypeUsernameMention }
+	case "comments":
+		filter = func(m *models.Message) bool { return m.Type == models.MessageTypeCommentReply }
+	case "selfreply":
+		filter = func(m *models.Message) bool { return m.Type == models.MessageTypePostReply }
+	default:
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	children := []models.RedditElement{}
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if m := s.messages[i]; filter(m) {
+			children = append(children, models.RedditElement{Kind: m.Name.Type(), Data: m})
+		}
+	}
+	children, before, after := paginate(r, children)
+	writeJSON(w, listingResponse(children, before, after))
+	if r.Form.Get("mark") == "true" {
+		for _, c := range children {
+			c.Data.(*models.Message).New = false
+		}
+	}
+}
+
+func (s *Server) handleMarkMessages(unread bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, id := range strings.Split(r.Form.Get("id"), ",") {
+			if m := s.message(models.RedditID(id)); m != nil {
+				m.New = unread
+			}
+		}
+		writeJSON(w, map[string]interface{}{})
+	}
+}
+
+func (s *Server) handleReadAllMessages(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.messages {
+		m.New = false
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleBlock(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.message(models.RedditID(r.Form.Get("id"))) == nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]interface{}{})
+}
+
+// uploadPath is where files are uploaded to after leasing them from /api/media/asset.json.
+const uploadPath = "/media-upload"
+
+// newPost checks the fields all kinds of posts have in common, and creates a post from them.
+// If they are invalid, an error is written to w and nil is returned.
+func newPost(w http.ResponseWriter, r *http.Request) *models.Post {
+	if r.Form.Get("sr") == "" {
+		writeJSONErrors(w, "SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr")
+		return nil
+	}
+	if r.Form.Get("title") == "" {
+		writeJSONErrors(w, "NO_TEXT", "we need something here", "title")
+		return nil
+	}
+	return &models.Post{
+		Author:         "miratest",
+		AuthorFullname: "t2_miratest",
+		Subreddit:      r.Form.Get("sr"),
+		Title:          r.Form.Get("title"),
+		Over18:         r.Form.Get("nsfw") == "true",
+		Spoiler:        r.Form.Get("spoiler") == "true",
+		SendReplies:    r.Form.Get("sendreplies") != "false",
+		LinkFlairText:  r.Form.Get("flair_text"),
+	}
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	p := newPost(w, r)
+	if p == nil {
+		return
+	}
+	kind := r.Form.Get("kind")
+	s.mu.Lock()
+	switch kind {
+	case "self", "":
+		p.IsSelf = true
+		p.Selftext = r.Form.Get("text")
+	case "link":
+		p.URL = r.Form.Get("url")
+	case "image", "video":
+		u := s.upload(r.Form.Get("url"))
+		if u == nil {
+			s.mu.Unlock()
+			writeJSONErrors(w, "NO_URL", "url isn't an uploaded file", "url")
+			return
+		}
+		p.URL = u.URL
+		p.IsRedditMediaDomain = true
+		p.IsVideo = kind == "video"
+		p.PostHint = kind
+		if p.IsVideo {
+			p.PostHint = "hosted:video"
+			p.Media = &models.Media{RedditVideo: &models.RedditVideo{FallbackURL: u.URL, TranscodingStatus: "completed"}}
+			p.SecureMedia = p.Media
+		}
+	case "crosspost":
+		parent, ok := s.things[models.RedditID(r.Form.Get("crosspost_fullname"))].(*models.Post)
+		if !ok {
+			s.mu.Unlock()
+			writeJSONErrors(w, "INVALID_CROSSPOST_THING", "that isn't a post", "crosspost_fullname")
+			return
+		}
+		parent.NumCrossposts++
+		p.URL = parent.Permalink
+		p.CrosspostParent = parent.Name
+		cp := *parent
+		p.CrosspostParentList = []*models.Post{&cp}
+	default:
+		s.mu.Unlock()
+		writeJSONErrors(w, "INVALID_OPTION", "that is not a valid kind", "kind")
+		return
+	}
+	s.mu.Unlock()
+	if p.URL == "" && !p.IsSelf {
+		writeJSONErrors(w, "NO_URL", "a url is required", "url")
+		return
+	}
+	if r.Form.Get("resubmit") != "true" && p.URL != "" && s.submitted(p.Subreddit, p.URL) {
+		writeJSONErrors(w, "ALREADY_SUB", "that link has already been submitted", "url")
+		return
+	}
+
+	p = s.AddPost(p)
+	if p.IsSelf {
+		p.URL = "https://www.reddit.com" + p.Permalink
+	}
+	data := map[string]interface{}{
+		"url":  p.URL,
+		"id":   p.ID,
+		"name": p.Name,
+	}
+	if kind == "image" || kind == "video" {
+		// reddit processes media posts in the background & doesn't return their ID
+		data = map[string]interface{}{
+			"user_submitted_page": "https://www.reddit.com/user/miratest/submitted/",
+			"websocket_url":       "wss://ws.miratest/" + p.ID,
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": []interface{}{},
+			"data":   data,
+		},
+	})
+}
+
+// submitted checks if a link has been posted to sr before.
+func (s *Server) submitted(sr, url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.list(sr, func(sub models.Submission) bool {
+		p, ok := sub.(*models.Post)
+		return ok && p.URL == url
+	})) > 0
+}
+
+func (s *Server) handleSubmitGallery(w http.ResponseWriter, r *http.Request) {
+	p := newPost(w, r)
+	if p == nil {
+		return
+	}
+	if len(r.Form["items"]) < 2 {
+		writeJSONErrors(w, "NOT_ENOUGH_ITEMS", "galleries need at least 2 items", "items")
+		return
+	}
+	p.IsGallery = true
+	p.GalleryData = &models.GalleryData{}
+	p.MediaMetadata = make(map[string]models.MediaMetadata)
+	s.mu.Lock()
+	for i, raw := range r.Form["items"] {
+		var item models.GalleryItem
+		json.Unmarshal([]byte(raw), &item)
+		u := s.assets[item.MediaID]
+		if u == nil || u.Data == nil {
+			s.mu.Unlock()
+			writeJSONErrors(w, "MEDIA_UPLOAD_FAILED", "that file wasn't uploaded", "items")
+			return
+		}
+		item.ID = int64(i + 1)
+		p.GalleryData.Items = append(p.GalleryData.Items, item)
+		p.MediaMetadata[item.MediaID] = models.MediaMetadata{
+			ID:       item.MediaID,
+			Status:   "valid",
+			Type:     "Image",
+			MimeType: u.MimeType,
+			Source:   models.MediaSource{URL: u.URL},
+		}
+	}
+	s.mu.Unlock()
+	p = s.AddPost(p)
+	p.URL = "https://www.reddit.com/gallery/" + p.ID
+	writeSubmitted(w, p)
+}
+
+func (s *Server) handleSubmitPoll(w http.ResponseWriter, r *http.Request) {
+	p := newPost(w, r)
+	if p == nil {
+		return
+	}
+	if n := len(r.Form["options"]); n < 2 || n > 6 {
+		writeJSONErrors(w, "INVALID_OPTION", "polls need 2 to 6 options", "options")
+		return
+	}
+	if d, _ := strconv.Atoi(r.Form.Get("duration")); d < 1 || d > 7 {
+		writeJSONErrors(w, "INVALID_OPTION", "polls can be open for 1 to 7 days", "duration")
+		return
+	}
+	p.IsSelf = true
+	p.Selftext = r.Form.Get("text")
+	p = s.AddPost(p)
+	p.URL = "https://www.reddit.com" + p.Permalink
+	writeSubmitted(w, p)
+}
+
+// writeSubmitted answers like the JSON submit endpoints do, with the full ID in "id".
+func writeSubmitted(w http.ResponseWriter, p *models.Post) {
+	writeJSON(w, map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": []interface{}{},
+			"data": map[string]interface{}{
+				"url": p.URL,
+				"id":  p.Name,
+			},
+		},
+	})
+}
+
+func (s *Server) handleMediaAsset(w http.ResponseWriter, r *http.Request) {
+	name, mimeType := r.Form.Get("filepath"), r.Form.Get("mimetype")
+	if name == "" || mimeType == "" {
+		writeJSONErrors(w, "BAD_REQUEST", "filepath & mimetype are required", "filepath")
+		return
+	}
+	s.mu.Lock()
+	id := s.newID()
+	key := "miratest/" + id + "/" + name
+	s.assets[id] = &Upload{AssetID: id, URL: s.URL + uploadPath + "/" + key, Name: name, MimeType: mimeType}
+	s.mu.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"args": map[string]interface{}{
+			"action": s.URL + uploadPath,
+			"fields": []map[string]string{
+				{"name": "key", "value": key},
+				{"name": "Content-Type", "value": mimeType},
+			},
+		},
+		"asset": map[string]interface{}{
+			"asset_id":         id,
+			"processing_state": "incomplete",
+			"payload":          map[string]string{"filepath": name},
+			"websocket_url":    "wss://ws.miratest/" + id,
+		},
+	})
+}
+
+func (s *Server) handleMediaUpload(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// keys look like miratest/{asset id}/{name}
+	parts := strings.SplitN(r.FormValue("key"), "/", 3)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(parts) < 3 || s.assets[parts[1]] == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	u := s.assets[parts[1]]
+	u.Data = data
+	s.uploads = append(s.uploads, u)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request) {
+	s.modAction(w, r, "approve", func(sub models.Submission) {
+		switch v := sub.(type) {
+		case *models.Post:
+			v.Approved, v.Removed, v.ApprovedBy = true, false, "miratest"
+		case *models.Comment:
+			v.Approved, v.Removed, v.ApprovedBy = true, false, "miratest"
+		}
+	})
+}
+
+func (s *Server) handleRemove(w http.ResponseWriter, r *http.Request) {
+	action := "remove"
+	if r.Form.Get("spam") == "true" {
+		action = "spam"
+	}
+	s.modAction(w, r, action, func(sub models.Submission) {
+		switch v := sub.(type) {
+		case *models.Post:
+			v.Approved, v.Removed = false, true
+		case *models.Comment:
+			v.Approved, v.Removed, v.Spam = false, true, action == "spam"
+		}
+	})
+}
+
+// modAction applies f to the thing given in the "id" parameter and adds
+// a "<action>link" or "<action>comment" entry to the mod log.
+func (s *Server) modAction(w http.ResponseWriter, r *http.Request, action string, f func(models.Submission)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.things[models.RedditID(r.Form.Get("id"))]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	f(sub)
+	target := "link"
+	if sub.GetID().Type() == models.KComment {
+		target = "comment"
+	}
+	s.addModAction(&models.ModAction{
+		Action:         models.ModActionType(action + target),
+		Mod:            "miratest",
+		Subreddit:      sub.GetSubreddit(),
+		TargetAuthor:   sub.GetAuthor(),
+		TargetFullname: sub.GetID(),
+		TargetTitle:    sub.GetTitle(),
+		TargetBody:     sub.GetBody(),
+	})
+	writeJSON(w, map[string]interface{}{})
+}
+
+// handleUserAction applies f to the post or comment in the "id" field.
+func (s *Server) handleUserAction(f func(models.Submission)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		sub, ok := s.things[models.RedditID(r.Form.Get("id"))]
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		f(sub)
+		writeJSON(w, map[string]interface{}{})
+	}
+}
+
+// handlePostAction applies f to the post in the "id" field.
+func (s *Server) handlePostAction(f func(*models.Post)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		p, ok := s.things[models.RedditID(r.Form.Get("id"))].(*models.Post)
+		if !ok {
+			writeError(w, http.StatusNotFound)
+			return
+		}
+		f(p)
+		writeJSON(w, map[string]interface{}{})
+	}
+}
+
+func setSaved(sub models.Submission, saved bool) {
+	switch v := sub.(type) {
+	case *models.Post:
+		v.Saved = saved
+	case *models.Comment:
+		v.Saved = saved
+	}
+}
+
+func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
+	dir, err := strconv.Atoi(r.Form.Get("dir"))
+	if err != nil || dir < -1 || dir > 1 {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	// Likes can't tell downvotes from no vote, check Actions for those
+	s.handleUserAction(func(sub models.Submission) {
+		switch v := sub.(type) {
+		case *models.Post:
+			v.Likes = dir == 1
+		case *models.Comment:
+			v.Likes = dir == 1
+		}
+	})(w, r)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	reason := r.Form.Get("reason")
+	if reason == "other" {
+		reason = r.Form.Get("other_reason")
+	}
+	if reason == "" {
+		writeJSONErrors(w, "NO_TEXT", "we need something here", "reason")
+		return
+	}
+	if len(reason) > 100 {
+		writeJSONErrors(w, "TOO_LONG", "this is too long (max: 100)", "reason")
+		return
+	}
+	id := models.RedditID(r.Form.Get("thing_id"))
+	if s.Submission(id) == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	s.Report(id, reason)
+	writeJSON(w, map[string]interface{}{"json": map[string]interface{}{"errors": []interface{}{}}})
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch v := s.things[models.RedditID(r.Form.Get("id"))].(type) {
+	case *models.Post:
+		v.Author, v.Selftext = "[deleted]", "[deleted]"
+	case *models.Comment:
+		v.Author, v.Body = "[deleted]", "[deleted]"
+	}
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) handleWiki(w http.ResponseWriter, r *http.Request) {
+	page := strings.TrimSuffix(r.PathValue("page"), ".json")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revs := s.wiki[wikiKey(r.PathValue("sr"), page)]
+	if len(revs) == 0 {
+		writeWikiNotFound(w)
+		return
+	}
+	wiki := revs[0]
+	if v := r.Form.Get("v"); v != "" {
+		if wiki = findRevision(revs, v); wiki == nil {
+			writeJSON(w, map[string]interface{}{"reason": "INVALID_REVISION", "message": "Not Found"}, http.StatusNotFound)
+			return
+		}
+	}
+	// like reddit, v2 shows that revision with the changes since v
+	if v2 := r.Form.Get("v2"); v2 != "" && r.Form.Get("v") != "" {
+		to := findRevision(revs, v2)
+		if to == nil {
+			writeJSON(w, map[string]interface{}{"reason": "INVALID_REVISION", "message": "Not Found"}, http.StatusNotFound)
+			return
+		}
+		diff := *to
+		diff.DiffContent = "<del>" + html.EscapeString(wiki.ContentMD) + "</del><ins>" + html.EscapeString(to.ContentMD) + "</ins>"
+		wiki = &diff
+	}
+	writeJSON(w, map[string]interface{}{"kind": "wikipage", "data": wiki})
+}
+
+// findRevision returns the revision with the given ID, or nil.
+func findRevision(revs []*models.Wiki, id string) *models.Wiki {
+	for _, rev := range revs {
+		if rev.RevisionID == id {
+			return rev
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleWikiPages(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := wikiKey(r.PathValue("sr"), "")
+	pages := []string{}
+	for key := range s.wiki {
+		if strings.HasPrefix(key, prefix) {
+			pages = append(pages, strings.TrimPrefix(key, prefix))
+		}
+	}
+	sort.Strings(pages)
+	writeJSON(w, map[string]interface{}{"kind": "wikipagelisting", "data": pages})
+}
+
+// handleWikiRevisions lists the revisions of a page, or of all pages. Like reddit, the items of the
+// Listing have no kind.
+func (s *Server) handleWikiRevisions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := wikiKey(r.PathValue("sr"), r.PathValue("page"))
+	if r.PathValue("page") != "" && len(s.wiki[key]) == 0 {
+		writeWikiNotFound(w)
+		return
+	}
+	revs := s.wikiRevisions(key)
+	if after := strings.TrimPrefix(r.Form.Get("after"), "WikiRevision_"); after != "" {
+		for i, rev := range revs {
+			if rev.ID == after {
+				revs = revs[i+1:]
+				break
+			}
+		}
+	}
+	limit, err := strconv.Atoi(r.Form.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 25
+	}
+	var after interface{}
+	if limit < len(revs) {
+		revs = revs[:limit]
+		after = "WikiRevision_" + revs[limit-1].ID
+	}
+	writeJSON(w, map[string]interface{}{
+		"kind": "Listing",
+		"data": map[string]interface{}{"children": revs, "after": after, "before": nil},
+	})
+}
+
+func (s *Server) handleWikiSettings(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings, ok := s.wikiSet[wikiKey(r.PathValue("sr"), r.PathValue("page"))]
+	if !ok {
+		writeWikiNotFound(w)
+		return
+	}
+	if r.Method == http.MethodPost {
+		level, err := strconv.Atoi(r.Form.Get("permlevel"))
+		if err != nil || level < 0 || level > 2 {
+			writeJSON(w, map[string]interface{}{"reason": "INVALID_PERMLEVEL", "message": "Bad Request"}, http.StatusBadRequest)
+			return
+		}
+		settings.PermLevel = models.WikiPermLevel(level)
+		settings.Listed = r.Form.Get("listed") == "true"
+	}
+	writeJSON(w, map[string]interface{}{"kind": "wikipagesettings", "data": settings})
+}
+
+// handleWikiEdit answers with 409 Conflict if previous isn't the current revision, like reddit.
+func (s *Server) handleWikiEdit(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sr, page := r.PathValue("sr"), r.Form.Get("page")
+	current := s.currentWiki(wikiKey(sr, page))
+	if prev := r.Form.Get("previous"); prev != "" && current != nil && current.RevisionID != prev {
+		writeJSON(w, map[string]interface{}{
+			"reason":      "EDIT_CONFLICT",
+			"message":     "Conflict",
+			"content":     r.Form.Get("content"),
+			"newcontent":  current.ContentMD,
+			"newrevision": current.RevisionID,
+			"diffcontent": "<del>" + html.EscapeString(current.ContentMD) + "</del><ins>" + html.EscapeString(r.Form.Get("content")) + "</ins>",
+		}, http.StatusConflict)
+		return
+	}
+	s.setWiki(sr, page, r.Form.Get("content"), r.Form.Get("reason"), "miratest")
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) handleWikiRevert(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sr, page := r.PathValue("sr"), r.Form.Get("page")
+	for _, rev := range s.wiki[wikiKey(sr, page)] {
+		if rev.RevisionID == r.Form.Get("revision") {
+			s.setWiki(sr, page, rev.ContentMD, "reverted back to revision "+rev.RevisionID, "miratest")
+			writeJSON(w, map[string]interface{}{})
+			return
+		}
+	}
+	writeWikiNotFound(w)
+}
+
+// handleWikiHide toggles if a revision is hidden, like reddit.
+func (s *Server) handleWikiHide(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rev := range s.wiki[wikiKey(r.PathValue("sr"), r.Form.Get("page"))] {
+		if id := rev.RevisionID; id == r.Form.Get("revision") {
+			s.hidden[id] = !s.hidden[id]
+			writeJSON(w, map[string]interface{}{"status": s.hidden[id]})
+			return
+		}
+	}
+	writeWikiNotFound(w)
+}
+
+func (s *Server) handleWikiEditor(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings, ok := s.wikiSet[wikiKey(r.PathValue("sr"), r.Form.Get("page"))]
+	name := r.Form.Get("username")
+	if !ok || name == "" {
+		writeWikiNotFound(w)
+		return
+	}
+	editors := settings.Editors[:0:0]
+	for _, e := range settings.Editors {
+		if u, ok := e.Data.(*models.Redditor); !ok || !strings.EqualFold(u.Name, name) {
+			editors = append(editors, e)
+		}
+	}
+	switch r.PathValue("act") {
+	case "add":
+		editors = append(editors, models.RedditElement{Kind: models.KRedditor, Data: &models.Redditor{Name: name}})
+	case "del":
+	default:
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	settings.Editors = editors
+	writeJSON(w, map[string]interface{}{})
+}
+
+func writeWikiNotFound(w http.ResponseWriter) {
+	writeJSON(w, map[string]interface{}{"reason": "PAGE_NOT_CREATED", "message": "Not Found"}, http.StatusNotFound)
+}
+
+func (s *Server) handleModmail(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conv, ok := s.modmail[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	if r.Form.Get("markRead") == "true" {
+		conv.Conversation.LastUnread = nil
+	}
+	writeJSON(w, conv)
+}
+
+// modmailFilters tells if a conversation is listed for a state filter of /api/mod/conversations.
+var modmailFilters = map[models.ModmailFilter]func(c *models.ModmailConversation) bool{
+	models.ModmailAll:           func(c *models.ModmailConversation) bool { return true },
+	models.ModmailNew:           func(c *models.ModmailConversation) bool { return c.State == models.ModmailStateNew },
+	models.ModmailInProgress:    func(c *models.ModmailConversation) bool { return c.State == models.ModmailStateInProgress },
+	models.ModmailArchived:      func(c *models.ModmailConversation) bool { return c.State == models.ModmailStateArchived },
+	models.ModmailAppeals:       func(c *models.ModmailConversation) bool { return c.State == models.ModmailStateAppeals },
+	models.ModmailJoinRequests:  func(c *models.ModmailConversation) bool { return c.State == models.ModmailStateJoinRequests },
+	models.ModmailFiltered:      func(c *models.ModmailConversation) bool { return c.State == models.ModmailStateFiltered },
+	models.ModmailHighlighted:   func(c *models.ModmailConversation) bool { return c.IsHighlighted },
+	models.ModmailMod:           func(c *models.ModmailConversation) bool { return c.IsInternal },
+	models.ModmailNotifications: func(c *models.ModmailConversation) bool { return c.IsAuto },
+}
+
+// handleModmailList lists conversations, most recently updated first. Like reddit, only the
+// last message of each conversation is included.
+func (s *Server) handleModmailList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := models.ModmailFilter(r.Form.Get("state"))
+	if state == "" {
+		state = models.ModmailAll
+	}
+	filter, ok := modmailFilters[state]
+	if !ok {
+		writeModmailError(w, "INVALID_OPTION", "state is not valid", "state")
+		return
+	}
+	var entities map[string]bool
+	if e := r.Form.Get("entity"); e != "" {
+		entities = make(map[string]bool)
+		for _, sr := range strings.Split(e, ",") {
+			entities[strings.ToLower(sr)] = true
+		}
+	}
+	var list []*models.NewModmailConversation
+	for _, conv := range s.modmail {
+		if filter(&conv.Conversation) && (entities == nil || entities[strings.ToLower(conv.Conversation.Owner.DisplayName)]) {
+			list = append(list, conv)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].CreatedAt(), list[j].CreatedAt()
+		if a.Equal(b) {
+			return list[i].Conversation.ID > list[j].Conversation.ID
+		}
+		return a.After(b)
+	})
+	if after := r.Form.Get("after"); after != "" {
+		for i, conv := range list {
+			if conv.Conversation.ID == after {
+				list = list[i+1:]
+				break
+			}
+		}
+	}
+	if limit, err := strconv.Atoi(r.Form.Get("limit")); err == nil && limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+	convs := make(map[string]models.ModmailConversation, len(list))
+	ids := make([]string, 0, len(list))
+	messages := make(map[string]models.ModmailMessage)
+	for _, conv := range list {
+		convs[conv.Conversation.ID] = conv.Conversation
+		ids = append(ids, conv.Conversation.ID)
+		for i := len(conv.Conversation.ObjIDs) - 1; i >= 0; i-- {
+			if obj := conv.Conversation.ObjIDs[i]; obj.Key == "messages" {
+				messages[obj.ID] = conv.Messages[obj.ID]
+				break
+			}
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"conversations":   convs,
+		"conversationIds": ids,
+		"messages":        messages,
+		"viewerId":        "t2_miratest",
+	})
+}
+
+func (s *Server) handleModmailCreate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, field := range []string{"srName", "subject", "body"} {
+		if r.Form.Get(field) == "" {
+			writeModmailError(w, "MISSING_FIELD", field+" is required", field)
+			return
+		}
+	}
+	sr := r.Form.Get("srName")
+	conv := &models.NewModmailConversation{
+		Conversation: models.ModmailConversation{
+			ID:          s.newID(),
+			Subject:     r.Form.Get("subject"),
+			IsRepliable: true,
+			Owner: models.ModmailOwner{
+				DisplayName: sr,
+				Type:        "subreddit",
+				ID:          models.RedditID("t5_" + strings.ToLower(sr)),
+			},
+		},
+		Messages:   make(map[string]models.ModmailMessage),
+		ModActions: make(map[string]models.ModmailModAction),
+	}
+	if to := r.Form.Get("to"); to != "" {
+		conv.Conversation.Participant = models.ModmailAuthor{Name: to, IsParticipant: true, ID: models.RedditID("t2_" + strings.ToLower(to))}
+		conv.User = models.ModmailUser{Name: to, ID: conv.Conversation.Participant.ID}
+	} else {
+		conv.Conversation.IsInternal = true
+	}
+	s.addModmailMessage(conv, models.ModmailMessage{
+		Body:   r.Form.Get("body"),
+		Author: modmailAuthor(r.Form.Get("isAuthorHidden") == "true"),
+	})
+	s.modmail[conv.Conversation.ID] = conv
+	writeJSON(w, conv)
+}
+
+func (s *Server) handleModmailReply(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conv, ok := s.modmail[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	if r.Form.Get("body") == "" {
+		writeModmailError(w, "MISSING_FIELD", "body is required", "body")
+		return
+	}
+	s.addModmailMessage(conv, models.ModmailMessage{
+		Body:       r.Form.Get("body"),
+		Author:     modmailAuthor(r.Form.Get("isAuthorHidden") == "true"),
+		IsInternal: r.Form.Get("isInternal") == "true",
+	})
+	writeJSON(w, conv)
+}
+
+// handleModmailAction handles archiving, highlighting, muting & banning from modmail.
+// Mutes & bans are added to the user lists of the subreddit.
+func (s *Server) handleModmailAction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conv, ok := s.modmail[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	c := &conv.Conversation
+	sr, user := c.Owner.DisplayName, c.Participant.Name
+	action := r.PathValue("action")
+	if r.Method == http.MethodDelete {
+		action = "unhighlight"
+	}
+	if user == "" && (action == "mute" || action == "unmute" || action == "temp_ban" || action == "unban") {
+		writeModmailError(w, "NO_PARTICIPANT", "the conversation has no participant", "")
+		return
+	}
+	switch action {
+	case "archive":
+		c.State = models.ModmailStateArchived
+		s.addModmailAction(conv, models.ModmailActionArchive)
+	case "unarchive":
+		c.State = models.ModmailStateInProgress
+		s.addModmailAction(conv, models.ModmailActionUnarchive)
+	case "highlight":
+		c.IsHighlighted = true
+		s.addModmailAction(conv, models.ModmailActionHighlight)
+	case "unhighlight":
+		c.IsHighlighted = false
+		s.addModmailAction(conv, models.ModmailActionUnhighlight)
+	case "mute":
+		hours, err := strconv.Atoi(r.Form.Get("num_hours"))
+		if err != nil || (hours != 72 && hours != 168 && hours != 672) {
+			writeModmailError(w, "INVALID_MUTE_LENGTH", "num_hours must be 72, 168 or 672", "num_hours")
+			return
+		}
+		end := time.Now().Add(time.Duration(hours) * time.Hour)
+		conv.User.MuteStatus = models.ModmailMuteStatus{IsMuted: true, EndDate: &end}
+		days := hours / 24
+		s.addRelationship(sr, "muted", &models.Relationship{Name: user, DaysLeft: &days})
+		s.addModmailAction(conv, models.ModmailActionMute)
+	case "unmute":
+		conv.User.MuteStatus = models.ModmailMuteStatus{}
+		s.removeRelationship(sr, "muted", user)
+		s.addModmailAction(conv, models.ModmailActionUnmute)
+	case "temp_ban":
+		days, err := strconv.Atoi(r.Form.Get("duration"))
+		if err != nil || days < 1 || days > 999 {
+			writeModmailError(w, "INVALID_BAN_LENGTH", "duration must be between 1 and 999", "duration")
+			return
+		}
+		end := time.Now().AddDate(0, 0, days)
+		conv.User.BanStatus = models.ModmailBanStatus{IsBanned: true, EndDate: &end}
+		s.addRelationship(sr, "banned", &models.Relationship{Name: user, DaysLeft: &days})
+		s.addModmailAction(conv, models.ModmailActionBan)
+	case "unban":
+		conv.User.BanStatus = models.ModmailBanStatus{}
+		s.removeRelationship(sr, "banned", user)
+		s.addModmailAction(conv, models.ModmailActionUnban)
+	default:
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, conv)
+}
+
+func (s *Server) handleModmailRead(unread bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, id := range strings.Split(r.Form.Get("conversationIds"), ",") {
+			conv, ok := s.modmail[id]
+			if !ok {
+				continue
+			}
+			if unread {
+				conv.Conversation.LastUnread = conv.Conversation.LastUpdated
+			} else {
+				conv.Conversation.LastUnread = nil
+			}
+		}
+		writeJSON(w, map[string]interface{}{})
+	}
+}
+
+func (s *Server) handleModmailUnreadCount(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := make(map[models.ModmailFilter]int)
+	for _, conv := range s.modmail {
+		if conv.Conversation.LastUnread == nil {
+			continue
+		}
+		for state, filter := range modmailFilters {
+			if state != models.ModmailAll && filter(&conv.Conversation) {
+				count[state]++
+			}
+		}
+	}
+	writeJSON(w, count)
+}
+
+// modmailAuthor returns the logged in user as the author of a modmail message.
+func modmailAuthor(hidden bool) models.ModmailAuthor {
+	return models.ModmailAuthor{Name: "miratest", IsMod: true, IsHidden: hidden, ID: "t2_miratest"}
+}
+
+// paginate applies the limit, before & after parameters of r to a listing.
+func paginate(r *http.Request, children []models.RedditElement) ([]models.RedditElement, string, string) {
+	start, end := 0, len(children)
+	if after := r.Form.Get("after"); after != "" {
+		start = end
+		for i, c := range children {
+			if string(c.Data.GetID()) == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if before := r.Form.Get("before"); before != "" {
+		for i, c := range children {
+			if string(c.Data.GetID()) == before {
+				end = i
+				break
+			}
+		}
+		if end < start {
+			end = start
+		}
+	}
+	limit, err := strconv.Atoi(r.Form.Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 25
+	}
+	if end-start > limit {
+		if r.Form.Get("before") != "" && r.Form.Get("after") == "" {
+			// before pages towards the newest items
+			start = end - limit
+		} else {
+			end = start + limit
+		}
+	}
+	page := children[start:end]
+
+	var before, after string
+	if len(page) > 0 && start > 0 {
+		before = string(page[0].Data.GetID())
+	}
+	if len(page) > 0 && end < len(children) {
+		after = string(page[len(page)-1].Data.GetID())
+	}
+	return page, before, after
+}
+
+func writePage(w http.ResponseWriter, r *http.Request, items []models.Submission) {
+	children := make([]models.RedditElement, 0, len(items))
+	for _, sub := range items {
+		children = append(children, models.RedditElement{Kind: sub.GetID().Type(), Data: sub})
+	}
+	children, before, after := paginate(r, children)
+	writeJSON(w, listingResponse(children, before, after))
+}
+
+func listing(items []models.Submission, before, after string) interface{} {
+	children := make([]models.RedditElement, 0, len(items))
+	for _, sub := range items {
+		children = append(children, models.RedditElement{Kind: sub.GetID().Type(), Data: sub})
+	}
+	return listingResponse(children, before, after)
+}
+
+func listingResponse(children []models.RedditElement, before, after string) interface{} {
+	return map[string]interface{}{
+		"kind": "Listing",
+		"data": models.Listing{
+			Dist:     len(children),
+			Children: children,
+			Before:   before,
+			After:    after,
+		},
+	}
+}
+
+func writeJSONErrors(w http.ResponseWriter, code, message, field string) {
+	writeJSON(w, map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": [][]string{{code, message, field}},
+		},
+	})
+}
+
+// writeModmailError writes an error like the modmail endpoints do.
+func writeModmailError(w http.ResponseWriter, reason, explanation, field string) {
+	writeJSON(w, map[string]interface{}{
+		"fields":      []string{field},
+		"explanation": explanation,
+		"message":     http.StatusText(http.StatusBadRequest),
+		"reason":      reason,
+	}, http.StatusBadRequest)
+}
+
+func writeError(w http.ResponseWriter, status int) {
+	writeJSON(w, map[string]interface{}{"message": http.StatusText(status), "error": status}, status)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}, status ...int) {
+	w.Header().Set("Content-Type", "application/json")
+	if len(status) > 0 {
+		w.WriteHeader(status[0])
+	}
+	json.NewEncoder(w).Encode(v)
+}
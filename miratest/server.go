@@ -0,0 +1,665 @@
+package miratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Token is the access token handed out by the Server.
+const Token = "miratest-token"
+
+// Action is a request that changed something on the Server (everything but GET requests).
+type Action struct {
+	Method string
+	Path   string
+	Form   url.Values
+}
+
+// Request is a request received by the Server. Token requests & uploads are not recorded.
+type Request struct {
+	Method string
+	Path   string
+	// Form contains the query & body parameters.
+	Form url.Values
+	Time time.Time
+}
+
+// Upload is a file uploaded for an image, video or gallery post.
+type Upload struct {
+	// AssetID & URL reference the file in posts.
+	AssetID  string
+	URL      string
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// fault is an injected error, see Fail.
+type fault struct {
+	path   string
+	n      int
+	status int
+	header http.Header
+	body   string
+}
+
+// Server is an in-memory fake of the reddit API. Create one with NewServer.
+// All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex // guards everything below
+	lastID   int64
+	things   map[models.RedditID]models.Submission
+	order    []models.RedditID // in order of creation
+	modlog   []*models.ModAction
+	wiki     map[string][]*models.Wiki       // key: sr/page, all revisions newest first
+	wikiSet  map[string]*models.WikiSettings // key: sr/page
+	hidden   map[string]bool                 // key: wiki revision ID
+	modmail  map[string]*models.NewModmailConversation
+	messages []*models.Message                 // in order of arrival
+	assets   map[string]*Upload                // key: asset ID, added when the upload is leased
+	uploads  []*Upload                         // in order of upload
+	users    map[string][]*models.Relationship // key: sr/type, newest first
+	actions  []Action
+	requests []Request
+	faults   []*fault
+	limit    int
+	limitDur time.Duration
+	used     int
+	reset    time.Time
+}
+
+// NewServer starts a new fake reddit server. Call Close once you are done.
+func NewServer() *Server {
+	s := &Server{
+		things:   make(map[models.RedditID]models.Submission),
+		wiki:     make(map[string][]*models.Wiki),
+		wikiSet:  make(map[string]*models.WikiSettings),
+		hidden:   make(map[string]bool),
+		modmail:  make(map[string]*models.NewModmailConversation),
+		assets:   make(map[string]*Upload),
+		users:    make(map[string][]*models.Relationship),
+		limit:    600,
+		limitDur: 10 * time.Minute,
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Credentials returns Credentials that can be passed to mira.Init to use the Server.
+func (s *Server) Credentials() mira.Credentials {
+	return mira.Credentials{
+		ClientID:     "miratest",
+		ClientSecret: "miratest",
+		Username:     "miratest",
+		Password:     "miratest",
+		UserAgent:    "miratest",
+		Endpoints: mira.Endpoints{
+			Base:  s.URL,
+			OAuth: s.URL,
+		},
+	}
+}
+
+// Reddit returns a Reddit instance that is logged in to the Server as /u/miratest.
+// Retries are sped up, so tests with injected errors don't take long.
+func (s *Server) Reddit() (*mira.Reddit, error) {
+	r := mira.Init(s.Credentials())
+	r.Values.Retry.MinBackoff = time.Millisecond
+	r.Values.Retry.MaxBackoff = 10 * time.Millisecond
+	if err := r.LoginAuth(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Actions returns all requests that changed something (everything but GET requests), in order.
+func (s *Server) Actions() []Action {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Action(nil), s.actions...)
+}
+
+// ActionsTo returns all Actions for the given path, i.e. "/api/approve".
+func (s *Server) ActionsTo(path string) []Action {
+	ret := []Action{}
+	for _, a := range s.Actions() {
+		if a.Path == path {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// Requests returns all requests received by the Server, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns all Requests for the given path, i.e. "/r/test/new.json".
+func (s *Server) RequestsTo(path string) []Request {
+	ret := []Request{}
+	for _, r := range s.Requests() {
+		if r.Path == path {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// Fail makes the next n requests to path (i.e. "/api/submit") fail with the given status code & body.
+// Use an empty path to fail requests to any path.
+func (s *Server) Fail(path string, n int, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{path: path, n: n, status: status, body: body})
+}
+
+// FailRateLimited makes the next n requests to path fail with 429 Too Many Requests and
+// a Retry-After header.
+func (s *Server) FailRateLimited(path string, n int, retryAfter time.Duration) {
+	s.FailRetryAfter(path, n, http.StatusTooManyRequests, retryAfter)
+}
+
+// FailRetryAfter makes the next n requests to path fail with the given status code and
+// a Retry-After header, like reddit does i.e. with 503 Service Unavailable during maintenance.
+func (s *Server) FailRetryAfter(path string, n int, status int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := http.Header{}
+	h.Set("Retry-After", strconv.FormatFloat(retryAfter.Seconds(), 'f', -1, 64))
+	body := fmt.Sprintf(`{"message": %q, "error": %d}`, http.StatusText(status), status)
+	s.faults = append(s.faults, &fault{path: path, n: n, status: status, header: h, body: body})
+}
+
+// Reject makes the next request to path be rejected by reddit with the given error code
+// (i.e. "RATELIMIT" or "SUBREDDIT_NOEXIST"), like it does for invalid submissions & comments.
+func (s *Server) Reject(path, code, message, field string) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"json": map[string]interface{}{
+			"errors": [][]string{{code, message, field}},
+		},
+	})
+	s.Fail(path, 1, http.StatusOK, string(body))
+}
+
+// SetRateLimit sets the request budget of the Server: limit requests per period.
+// The budget is reset immediately.
+func (s *Server) SetRateLimit(limit int, period time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.limitDur = period
+	s.used = 0
+	s.reset = time.Time{}
+}
+
+// AddPost adds a post to the Server. Name, ID & CreatedUTC are set if empty.
+// The (updated) post is returned.
+func (s *Server) AddPost(p *models.Post) *models.Post {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.ID == "" {
+		p.ID = s.newID()
+	}
+	if p.Name == "" {
+		p.Name = models.RedditID(string(models.KPost) + "_" + p.ID)
+	}
+	if p.CreatedUTC == 0 {
+		p.CreatedUTC = float64(time.Now().Unix())
+	}
+	if p.SubredditNamePrefixed == "" {
+		p.SubredditNamePrefixed = "r/" + p.Subreddit
+	}
+	if p.Permalink == "" {
+		p.Permalink = fmt.Sprintf("/r/%s/comments/%s/", p.Subreddit, p.ID)
+	}
+	s.add(p)
+	return p
+}
+
+// AddComment adds a comment to the Server. LinkID should be set to the post the comment
+// belongs to. ParentID defaults to LinkID, and Subreddit to the subreddit of the post.
+// Name, ID & CreatedUTC are set if empty. The (updated) comment is returned.
+func (s *Server) AddComment(c *models.Comment) *models.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.ID == "" {
+		c.ID = s.newID()
+	}
+	if c.Name == "" {
+		c.Name = models.RedditID(string(models.KComment) + "_" + c.ID)
+	}
+	if c.CreatedUTC == 0 {
+		c.CreatedUTC = float64(time.Now().Unix())
+	}
+	if c.ParentID == "" {
+		c.ParentID = c.LinkID
+	}
+	if p, ok := s.things[c.LinkID].(*models.Post); ok {
+		if c.Subreddit == "" {
+			c.Subreddit = p.Subreddit
+		}
+		p.NumComments++
+	}
+	if c.SubredditNamePrefixed == "" {
+		c.SubredditNamePrefixed = "r/" + c.Subreddit
+	}
+	if c.Permalink == "" {
+		c.Permalink = fmt.Sprintf("/r/%s/comments/%s/_/%s/", c.Subreddit, strings.TrimPrefix(string(c.LinkID), "t3_"), c.ID)
+	}
+	s.add(c)
+	return c
+}
+
+// Report adds a user report to a post or comment, as if a user reported it.
+func (s *Server) Report(id models.RedditID, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch v := s.things[id].(type) {
+	case *models.Post:
+		v.NumReports++
+		v.UserReports = addReport(v.UserReports, reason)
+	case *models.Comment:
+		v.NumReports++
+		v.UserReports = addReport(v.UserReports, reason)
+	}
+}
+
+func addReport(reports []models.UserReport, reason string) []models.UserReport {
+	for i := range reports {
+		if reports[i].Reason == reason {
+			reports[i].Count++
+			return reports
+		}
+	}
+	return append(reports, models.UserReport{Reason: reason, Count: 1})
+}
+
+// AddRelationship adds a redditor to a user list of a subreddit. listType is the type used by
+// /api/friend: "banned", "muted", "wikibanned", "contributor", "moderator" or "moderator_invite".
+// An existing entry of the redditor is replaced. RelID, UserID & Date are set if empty.
+// The (updated) entry is returned.
+func (s *Server) AddRelationship(sr, listType string, rel *models.Relationship) *models.Relationship {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addRelationship(sr, listType, rel)
+	return rel
+}
+
+// Relationships returns the user list of a subreddit, newest first. See AddRelationship for the types.
+func (s *Server) Relationships(sr, listType string) []*models.Relationship {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*models.Relationship(nil), s.users[sr+"/"+listType]...)
+}
+
+// AddModAction adds an entry to the mod log of a subreddit. ID & CreatedUTC are set if empty.
+func (s *Server) AddModAction(a *models.ModAction) *models.ModAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addModAction(a)
+	return a
+}
+
+// SetWiki creates or replaces a wiki page.
+func (s *Server) SetWiki(sr, page, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setWiki(sr, page, content, "", "miratest")
+}
+
+// AddModmail adds a new modmail conversation. Conversation.ID is set if empty. Conversation.ObjIDs,
+// NumMessages & LastUpdated are derived from Messages if empty.
+func (s *Server) AddModmail(conv *models.NewModmailConversation) *models.NewModmailConversation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if conv.Conversation.ID == "" {
+		conv.Conversation.ID = s.newID()
+	}
+	if conv.Messages == nil {
+		conv.Messages = make(map[string]models.ModmailMessage)
+	}
+	if conv.ModActions == nil {
+		conv.ModActions = make(map[string]models.ModmailModAction)
+	}
+	if len(conv.Conversation.ObjIDs) == 0 {
+		for _, msg := range conv.SortedMessages() {
+			conv.Conversation.ObjIDs = append(conv.Conversation.ObjIDs, models.ModmailObjID{ID: msg.ID, Key: "messages"})
+		}
+	}
+	if conv.Conversation.NumMessages == 0 {
+		conv.Conversation.NumMessages = len(conv.Messages)
+	}
+	if conv.Conversation.LastUpdated == nil {
+		updated := time.Now()
+		if msgs := conv.SortedMessages(); len(msgs) > 0 {
+			updated = msgs[len(msgs)-1].Date
+		}
+		conv.Conversation.LastUpdated = &updated
+	}
+	s.modmail[conv.Conversation.ID] = conv
+	return conv
+}
+
+// AddModmailMessage adds a message to a modmail conversation, i.e. to simulate a reply by the
+// participant. ID & Date are set if empty. The conversation is updated like reddit does: messages
+// by non-moderators mark it unread & move archived conversations back to in progress.
+// Returns false if the conversation doesn't exist.
+func (s *Server) AddModmailMessage(conversationID string, msg models.ModmailMessage) (models.ModmailMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conv, ok := s.modmail[conversationID]
+	if !ok {
+		return msg, false
+	}
+	return s.addModmailMessage(conv, msg), true
+}
+
+// Modmail returns a modmail conversation, or nil if it doesn't exist.
+func (s *Server) Modmail(conversationID string) *models.NewModmailConversation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.modmail[conversationID]
+}
+
+// AddMessage delivers a message to the inbox of the logged in user. For comment replies &
+// mentions, set WasComment and Type. Set New to make the message unread. Name, ID, Dest,
+// Type & CreatedUTC are set if empty. The (updated) message is returned.
+func (s *Server) AddMessage(m *models.Message) *models.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.Dest == "" {
+		m.Dest = "miratest"
+	}
+	s.addMessage(m)
+	return m
+}
+
+// Messages returns all messages sent to or by the logged in user, newest first.
+func (s *Server) Messages() []*models.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]*models.Message, 0, len(s.messages))
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		ret = append(ret, s.messages[i])
+	}
+	return ret
+}
+
+// Uploads returns all files uploaded to the Server, in order of upload.
+func (s *Server) Uploads() []Upload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]Upload, 0, len(s.uploads))
+	for _, u := range s.uploads {
+		ret = append(ret, *u)
+	}
+	return ret
+}
+
+// Submission returns a post or comment stored on the Server, or nil.
+func (s *Server) Submission(id models.RedditID) models.Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.things[id]
+}
+
+// Submissions returns all posts & comments in a subreddit, newest first.
+func (s *Server) Submissions(sr string) []models.Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(sr, func(models.Submission) bool { return true })
+}
+
+// ModLog returns the mod log of a subreddit, newest first.
+func (s *Server) ModLog(sr string) []*models.ModAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := []*models.ModAction{}
+	for i := len(s.modlog) - 1; i >= 0; i-- {
+		if strings.EqualFold(s.modlog[i].Subreddit, sr) {
+			ret = append(ret, s.modlog[i])
+		}
+	}
+	return ret
+}
+
+// Wiki returns a wiki page, or nil if it doesn't exist.
+func (s *Server) Wiki(sr, page string) *models.Wiki {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentWiki(wikiKey(sr, page))
+}
+
+func (s *Server) newID() string {
+	s.lastID++
+	return strconv.FormatInt(s.lastID+36*36*36*36, 36)
+}
+
+func (s *Server) add(sub models.Submission) {
+	if _, ok := s.things[sub.GetID()]; !ok {
+		s.order = append(s.order, sub.GetID())
+	}
+	s.things[sub.GetID()] = sub
+}
+
+func (s *Server) addModAction(a *models.ModAction) {
+	if a.ID == "" {
+		a.ID = "ModAction_" + s.newID()
+	}
+	if a.CreatedUTC == 0 {
+		a.CreatedUTC = float64(time.Now().Unix())
+	}
+	if a.SubredditNamePrefixed == "" {
+		a.SubredditNamePrefixed = "r/" + a.Subreddit
+	}
+	s.modlog = append(s.modlog, a)
+}
+
+func (s *Server) addMessage(m *models.Message) {
+	if m.ID == "" {
+		m.ID = s.newID()
+	}
+	if m.Name == "" {
+		kind := models.KMessage
+		if m.WasComment {
+			kind = models.KComment
+		}
+		m.Name = models.RedditID(string(kind) + "_" + m.ID)
+	}
+	if m.Type == "" && !m.WasComment {
+		m.Type = models.MessageTypePrivate
+	}
+	if m.CreatedUTC == 0 {
+		m.CreatedUTC = float64(time.Now().Unix())
+	}
+	s.messages = append(s.messages, m)
+}
+
+func (s *Server) addRelationship(sr, listType string, rel *models.Relationship) {
+	if rel.RelID == "" {
+		rel.RelID = models.RedditID("rb_" + s.newID())
+	}
+	if rel.UserID == "" {
+		rel.UserID = models.RedditID("t2_" + strings.ToLower(rel.Name))
+	}
+	if rel.Date == 0 {
+		rel.Date = float64(time.Now().Unix())
+	}
+	s.removeRelationship(sr, listType, rel.Name)
+	key := sr + "/" + listType
+	s.users[key] = append([]*models.Relationship{rel}, s.users[key]...)
+}
+
+// removeRelationship removes a redditor from a user list & returns true if it was on it.
+func (s *Server) removeRelationship(sr, listType, name string) bool {
+	key := sr + "/" + listType
+	for i, rel := range s.users[key] {
+		if strings.EqualFold(rel.Name, name) {
+			s.users[key] = append(s.users[key][:i:i], s.users[key][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) addModmailMessage(conv *models.NewModmailConversation, msg models.ModmailMessage) models.ModmailMessage {
+	if msg.ID == "" {
+		msg.ID = s.newID()
+	}
+	if msg.Date.IsZero() {
+		msg.Date = time.Now()
+	}
+	if msg.BodyMarkdown == "" {
+		msg.BodyMarkdown = msg.Body
+	}
+	c := &conv.Conversation
+	date := msg.Date
+	c.LastUpdated = &date
+	if msg.Author.IsMod {
+		c.LastModUpdate = &date
+		if c.State == models.ModmailStateNew && !msg.IsInternal {
+			c.State = models.ModmailStateInProgress
+		}
+	} else {
+		c.LastUserUpdate = &date
+		c.LastUnread = &date
+		if c.State == models.ModmailStateArchived {
+			c.State = models.ModmailStateInProgress
+		}
+	}
+	if conv.Messages == nil {
+		conv.Messages = make(map[string]models.ModmailMessage)
+	}
+	conv.Messages[msg.ID] = msg
+	c.ObjIDs = append(c.ObjIDs, models.ModmailObjID{ID: msg.ID, Key: "messages"})
+	c.NumMessages++
+	return msg
+}
+
+func (s *Server) addModmailAction(conv *models.NewModmailConversation, action models.ModmailActionType) {
+	a := models.ModmailModAction{
+		ID:           s.newID(),
+		Date:         time.Now(),
+		ActionTypeID: action,
+		Author:       models.ModmailAuthor{Name: "miratest", IsMod: true, ID: "t2_miratest"},
+	}
+	if conv.ModActions == nil {
+		conv.ModActions = make(map[string]models.ModmailModAction)
+	}
+	conv.ModActions[a.ID] = a
+	conv.Conversation.ObjIDs = append(conv.Conversation.ObjIDs, models.ModmailObjID{ID: a.ID, Key: "modActions"})
+}
+
+// upload returns the uploaded file with the given URL, or nil. s.mu must be held.
+func (s *Server) upload(url string) *Upload {
+	for _, u := range s.uploads {
+		if u.URL == url {
+			return u
+		}
+	}
+	return nil
+}
+
+// message returns the message with the given name. s.mu must be held.
+func (s *Server) message(name models.RedditID) *models.Message {
+	for _, m := range s.messages {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *Server) setWiki(sr, page, content, reason, author string) {
+	key := wikiKey(sr, page)
+	wiki := &models.Wiki{
+		ContentMD:    content,
+		MayRevise:    true,
+		Reason:       reason,
+		RevisionDate: int(time.Now().Unix()),
+		RevisionBy: models.RedditElement{
+			Kind: models.KRedditor,
+			Data: &models.Redditor{Name: author},
+		},
+		RevisionID: s.newID(),
+	}
+	s.wiki[key] = append([]*models.Wiki{wiki}, s.wiki[key]...)
+	if _, ok := s.wikiSet[key]; !ok {
+		s.wikiSet[key] = &models.WikiSettings{Listed: true, Editors: []models.RedditElement{}}
+	}
+}
+
+// currentWiki returns the current revision of a wiki page, or nil. s.mu must be held.
+func (s *Server) currentWiki(key string) *models.Wiki {
+	if revs := s.wiki[key]; len(revs) > 0 {
+		return revs[0]
+	}
+	return nil
+}
+
+// wikiRevisions returns the revisions of the wiki pages matching key (sr/page, or sr/ for all
+// pages of a subreddit), newest first. s.mu must be held.
+func (s *Server) wikiRevisions(key string) []*models.WikiRevision {
+	var ret []*models.WikiRevision
+	for k, revs := range s.wiki {
+		if k != key && !(strings.HasSuffix(key, "/") && strings.HasPrefix(k, key)) {
+			continue
+		}
+		_, page, _ := strings.Cut(k, "/")
+		for _, wiki := range revs {
+			author := wiki.RevisionBy
+			ret = append(ret, &models.WikiRevision{
+				ID:        wiki.RevisionID,
+				Page:      page,
+				Reason:    wiki.Reason,
+				Timestamp: float64(wiki.RevisionDate),
+				Author:    &author,
+				Hidden:    s.hidden[wiki.RevisionID],
+			})
+		}
+	}
+	// IDs are increasing, so they sort revisions made in the same second
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Timestamp == ret[j].Timestamp {
+			return ret[i].ID > ret[j].ID
+		}
+		return ret[i].Timestamp > ret[j].Timestamp
+	})
+	return ret
+}
+
+// list returns all things in sr (which may be a multi "a+b") matching f, newest first.
+func (s *Server) list(sr string, f func(models.Submission) bool) []models.Submission {
+	subs := map[string]bool{}
+	for _, name := range strings.Split(strings.ToLower(sr), "+") {
+		subs[name] = true
+	}
+	ret := []models.Submission{}
+	for i := len(s.order) - 1; i >= 0; i-- {
+		sub := s.things[s.order[i]]
+		if (subs["all"] || subs[strings.ToLower(sub.GetSubreddit())]) && f(sub) {
+			ret = append(ret, sub)
+		}
+	}
+	return ret
+}
+
+func wikiKey(sr, page string) string {
+	return strings.ToLower(sr) + "/" + strings.ToLower(page)
+}
@@ -54,6 +54,13 @@ func (c Comment) GetURL() string { return fmt.Sprintf("https://www.reddit.com%s"
 // GetCreated returns the creation date of the Comment
 func (c Comment) GetCreated() time.Time { return time.Unix(int64(c.CreatedUTC), 0) }
 
+// IsEdited tells you if the Comment has been edited
+func (c Comment) IsEdited() bool { return c.Edited.IsEdited }
+
+// EditedAt returns the time the Comment was last edited. It is zero if it hasn't been edited,
+// or reddit didn't keep the time.
+func (c Comment) EditedAt() time.Time { return c.Edited.At }
+
 // GetBanned returns the mod & time who deleted the Comment
 func (c Comment) GetBanned() SubModAction {
 	var mod string
@@ -15,7 +15,7 @@ type Comment struct {
 	LinkID                     RedditID            `json:"link_id"`
 	AuthorFlairTemplateID      string              `json:"author_flair_template_id"`
 	Likes                      bool                `json:"likes"`
-	Replies                    json.RawMessage     `json:"replies"` // is []Response, but reddit likes to fuck with us :(
+	Replies                    Replies             `json:"replies"`
 	UserReports                []UserReport        `json:"user_reports"`
 	Saved                      bool                `json:"saved"`
 	ID                         string              `json:"id"`
@@ -37,7 +37,7 @@ type Comment struct {
 	AllAwardings               []PostAward         `json:"all_awardings"`
 	SubredditID                RedditID            `json:"subreddit_id"`
 	Body                       string              `json:"body"`
-	Edited                     json.RawMessage     `json:"edited"` // bool or float64
+	Edited                     Edited              `json:"edited"`
 	Gildings                   map[string]int      `json:"gildings"`
 	AuthorFlairCSSClass        string              `json:"author_flair_css_class"`
 	Name                       RedditID            `json:"name"`
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Replies are the replies to a post or comment: the comments reddit sent,
+// and placeholders for the ones it left out.
+type Replies struct {
+	Comments []*Comment
+	More     []*More
+}
+
+// UnmarshalJSON parses the replies of a comment. Reddit sends an empty string
+// if there are no replies, and a Listing otherwise.
+func (r *Replies) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte(`""`)) || bytes.Equal(data, []byte("null")) {
+		*r = Replies{}
+		return nil
+	}
+	resp := &Response{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return err
+	}
+	list, ok := resp.Data.(*Listing)
+	if !ok {
+		return fmt.Errorf("couldn't convert replies to Listing struct. Data has Kind '%s'", resp.Kind)
+	}
+	*r = Replies{}
+	r.add(list.Children)
+	return nil
+}
+
+// MarshalJSON converts Replies back into the format used by reddit.
+func (r Replies) MarshalJSON() ([]byte, error) {
+	if r.Len() == 0 {
+		return []byte(`""`), nil
+	}
+	children := make([]RedditElement, 0, len(r.Comments)+len(r.More))
+	for _, c := range r.Comments {
+		children = append(children, RedditElement{Kind: KComment, Data: c})
+	}
+	for _, m := range r.More {
+		children = append(children, RedditElement{Kind: KMore, Data: m})
+	}
+	return json.Marshal(struct {
+		Kind responseType `json:"kind"`
+		Data Listing      `json:"data"`
+	}{rListing, Listing{Children: children}})
+}
+
+// Len returns the number of comments & placeholders.
+func (r Replies) Len() int { return len(r.Comments) + len(r.More) }
+
+func (r *Replies) add(children []RedditElement) {
+	for _, child := range children {
+		switch v := child.Data.(type) {
+		case *Comment:
+			r.Comments = append(r.Comments, v)
+		case *More:
+			r.More = append(r.More, v)
+		}
+	}
+}
+
+// CommentTree is a post with its comments. Replies to each comment are found in Comment.Replies.
+type CommentTree struct {
+	Post    *Post
+	Replies Replies
+}
+
+// NewCommentTree creates a CommentTree from the two listings returned by /comments/{id}.
+func NewCommentTree(post *Post, comments *Listing) *CommentTree {
+	t := &CommentTree{Post: post}
+	if comments != nil {
+		t.Replies.add(comments.Children)
+	}
+	return t
+}
+
+// Walk calls f for every comment in the tree (depth first), with its depth (0 for top level comments).
+// If f returns false, the replies of that comment are skipped.
+func (t *CommentTree) Walk(f func(c *Comment, depth int) bool) {
+	var walk func(r *Replies, depth int)
+	walk = func(r *Replies, depth int) {
+		for _, c := range r.Comments {
+			if f(c, depth) {
+				walk(&c.Replies, depth+1)
+			}
+		}
+	}
+	walk(&t.Replies, 0)
+}
+
+// Find returns the comment with the given ID, or nil if it isn't in the tree.
+func (t *CommentTree) Find(id RedditID) *Comment {
+	var found *Comment
+	t.Walk(func(c *Comment, _ int) bool {
+		if c.Name == id {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+// Len returns the number of comments in the tree.
+func (t *CommentTree) Len() int {
+	n := 0
+	t.Walk(func(*Comment, int) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// More returns all placeholders in the tree, i.e. comments that haven't been loaded yet.
+func (t *CommentTree) More() []*More {
+	ret := append([]*More{}, t.Replies.More...)
+	t.Walk(func(c *Comment, _ int) bool {
+		ret = append(ret, c.Replies.More...)
+		return true
+	})
+	return ret
+}
+
+// Insert adds things (comments & placeholders) to the tree below their parent, i.e. the ones
+// returned from /api/morechildren. Parents have to be in the tree or precede their children in things.
+// Things without a parent in the tree are ignored.
+func (t *CommentTree) Insert(things []RedditElement) {
+	parents := map[RedditID]*Replies{t.Post.Name: &t.Replies}
+	indexReplies(parents, &t.Replies)
+	for _, thing := range things {
+		var parentID RedditID
+		switch v := thing.Data.(type) {
+		case *Comment:
+			parentID = v.ParentID
+		case *More:
+			parentID = v.ParentID
+		default:
+			continue
+		}
+		r, ok := parents[parentID]
+		if !ok {
+			continue
+		}
+		r.add([]RedditElement{thing})
+		if c, ok := thing.Data.(*Comment); ok {
+			parents[c.Name] = &c.Replies
+			// replies sent along with the comment are already in place
+			indexReplies(parents, &c.Replies)
+		}
+	}
+}
+
+// indexReplies adds all comments below r to parents.
+func indexReplies(parents map[RedditID]*Replies, r *Replies) {
+	for _, c := range r.Comments {
+		parents[c.Name] = &c.Replies
+		indexReplies(parents, &c.Replies)
+	}
+}
+
+// RemoveMore removes a placeholder from the tree, i.e. after the comments it stands for have been loaded.
+func (t *CommentTree) RemoveMore(m *More) {
+	remove := func(r *Replies) {
+		for i, v := range r.More {
+			if v == m {
+				r.More = append(r.More[:i], r.More[i+1:]...)
+				return
+			}
+		}
+	}
+	remove(&t.Replies)
+	t.Walk(func(c *Comment, _ int) bool {
+		remove(&c.Replies)
+		return true
+	})
+}
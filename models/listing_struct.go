@@ -33,19 +33,32 @@ func (r *RedditElement) UnmarshalJSON(data []byte) error {
 
 	switch m.Kind {
 	case KComment:
-		r.Data = &Comment{}
+		// inbox items like comment replies are comments with the fields of a message
+		var probe struct {
+			WasComment bool `json:"was_comment"`
+		}
+		if err := json.Unmarshal(m.Data, &probe); err != nil {
+			return err
+		}
+		if probe.WasComment {
+			r.Data = &Message{}
+		} else {
+			r.Data = &Comment{}
+		}
 	case KRedditor:
 		r.Data = &Redditor{}
 	case KPost:
 		r.Data = &Post{}
-	//case KMessage:
-	// not implemented
+	case KMessage:
+		r.Data = &Message{}
 	case KSubreddit:
 		r.Data = &Subreddit{}
 	//case KAward:
 	// not implemented
 	case KModAction:
 		r.Data = &ModAction{}
+	case KMore:
+		r.Data = &More{}
 	default:
 		return fmt.Errorf("%q is an invalid RedditKind", m.Kind)
 	}
@@ -57,7 +70,7 @@ func (r *RedditElement) UnmarshalJSON(data []byte) error {
 // CommentActionResponse is returned by reddit when you create a comment (new or reply)
 type CommentActionResponse struct {
 	JSON struct {
-		Errors []string `json:"errors"`
+		Errors [][]string `json:"errors"`
 		Data   struct {
 			Things []RedditElement `json:"things"`
 		}
@@ -67,7 +80,7 @@ type CommentActionResponse struct {
 // PostActionResponse is returned by reddit when you create a post
 type PostActionResponse struct {
 	JSON struct {
-		Errors []string `json:"errors"`
+		Errors [][]string `json:"errors"`
 		Data   struct {
 			Name RedditID `json:"name"`
 			URL  string   `json:"url"`
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"html"
+	"sort"
+)
+
+// MediaURLs returns the URLs of all images & videos of the Post, regardless of its kind:
+// the items of galleries in order, the video of video posts, the image of image posts,
+// images embedded in text posts, and the preview of everything else. Crossposts return
+// the media of the crossposted post. Returns nil if the Post has no media.
+func (p Post) MediaURLs() []string {
+	if urls := p.GalleryURLs(); len(urls) > 0 {
+		return urls
+	}
+	if u := p.VideoURL(); u != "" {
+		return []string{u}
+	}
+	if p.PostHint == "image" && p.URL != "" {
+		return []string{p.URL}
+	}
+	var urls []string
+	if u := p.PreviewURL(); u != "" {
+		urls = append(urls, u)
+	}
+	if len(p.MediaMetadata) > 0 && !p.IsGallery {
+		// images embedded in text posts
+		ids := make([]string, 0, len(p.MediaMetadata))
+		for id := range p.MediaMetadata {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if u := p.MediaMetadata[id].URL(); u != "" {
+				urls = append(urls, u)
+			}
+		}
+	}
+	if len(urls) == 0 && len(p.CrosspostParentList) > 0 {
+		return p.CrosspostParentList[0].MediaURLs()
+	}
+	return urls
+}
+
+// GalleryURLs returns the URLs of the items of a gallery post in order. Items reddit hasn't
+// processed yet are left out. Crossposts of galleries return the items of the crossposted gallery.
+func (p Post) GalleryURLs() []string {
+	if p.GalleryData == nil {
+		if len(p.CrosspostParentList) > 0 {
+			return p.CrosspostParentList[0].GalleryURLs()
+		}
+		return nil
+	}
+	urls := make([]string, 0, len(p.GalleryData.Items))
+	for _, item := range p.GalleryData.Items {
+		if u := p.MediaMetadata[item.MediaID].URL(); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// PreviewURL returns the URL of the first preview image in its best resolution, or "" if there is none.
+func (p Post) PreviewURL() string {
+	if len(p.Preview.Images) == 0 {
+		return ""
+	}
+	img := p.Preview.Images[0]
+	if img.Source.URL != "" {
+		return html.UnescapeString(img.Source.URL)
+	}
+	// resolutions are sorted from small to large
+	if n := len(img.Resolutions); n > 0 {
+		return html.UnescapeString(img.Resolutions[n-1].URL)
+	}
+	return ""
+}
+
+// VideoURL returns the fallback URL of a video hosted by reddit, or "" if the Post has none.
+// GIFs converted to videos by reddit are included. Crossposts return the video of the crossposted post.
+func (p Post) VideoURL() string {
+	for _, m := range []*Media{p.SecureMedia, p.Media} {
+		if m != nil && m.RedditVideo != nil && m.RedditVideo.FallbackURL != "" {
+			return html.UnescapeString(m.RedditVideo.FallbackURL)
+		}
+	}
+	if v := p.Preview.RedditVideoPreview; v != nil && v.FallbackURL != "" {
+		return html.UnescapeString(v.FallbackURL)
+	}
+	if len(p.CrosspostParentList) > 0 {
+		return p.CrosspostParentList[0].VideoURL()
+	}
+	return ""
+}
+
+// URL returns the URL of the file in its original resolution: the image, the MP4 of animated
+// images, or the HLS playlist of videos. Returns "" if reddit hasn't processed the file.
+func (m MediaMetadata) URL() string {
+	if m.Status != "" && m.Status != "valid" {
+		return ""
+	}
+	var u string
+	switch {
+	case m.Source.URL != "":
+		u = m.Source.URL
+	case m.Source.MP4 != "":
+		u = m.Source.MP4
+	case m.Source.GIF != "":
+		u = m.Source.GIF
+	default:
+		u = m.HLSURL
+	}
+	return html.UnescapeString(u)
+}
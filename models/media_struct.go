@@ -0,0 +1,116 @@
+package models
+
+// Media is a video or embedded content (i.e. from youtube) of a post.
+type Media struct {
+	// RedditVideo is set for videos hosted by reddit.
+	RedditVideo *RedditVideo `json:"reddit_video"`
+	// Type is the provider of embedded content, i.e. "youtube.com".
+	Type   string  `json:"type"`
+	OEmbed *OEmbed `json:"oembed"`
+}
+
+// RedditVideo is a video hosted on v.redd.it.
+type RedditVideo struct {
+	// FallbackURL links to the video without sound, playable everywhere.
+	FallbackURL       string `json:"fallback_url"`
+	ScrubberMediaURL  string `json:"scrubber_media_url"`
+	DashURL           string `json:"dash_url"`
+	HLSURL            string `json:"hls_url"`
+	Width             uint   `json:"width"`
+	Height            uint   `json:"height"`
+	Duration          int    `json:"duration"` // seconds
+	BitrateKbps       int    `json:"bitrate_kbps"`
+	HasAudio          bool   `json:"has_audio"`
+	IsGIF             bool   `json:"is_gif"`
+	TranscodingStatus string `json:"transcoding_status"`
+}
+
+// OEmbed describes embedded content, see https://oembed.com
+type OEmbed struct {
+	Type            string `json:"type"`
+	Version         string `json:"version"`
+	Title           string `json:"title"`
+	AuthorName      string `json:"author_name"`
+	AuthorURL       string `json:"author_url"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	HTML            string `json:"html"`
+	Width           uint   `json:"width"`
+	Height          uint   `json:"height"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	ThumbnailWidth  uint   `json:"thumbnail_width"`
+	ThumbnailHeight uint   `json:"thumbnail_height"`
+}
+
+// MediaEmbed is the HTML to embed the Media of a post.
+type MediaEmbed struct {
+	Content        string `json:"content"`
+	Width          uint   `json:"width"`
+	Height         uint   `json:"height"`
+	Scrolling      bool   `json:"scrolling"`
+	MediaDomainURL string `json:"media_domain_url"`
+}
+
+// Preview contains images reddit generated from the content of a post.
+type Preview struct {
+	Images []PreviewImage `json:"images"`
+	// RedditVideoPreview is a video generated from GIFs & some embedded videos.
+	RedditVideoPreview *RedditVideo `json:"reddit_video_preview"`
+	Enabled            bool         `json:"enabled"`
+}
+
+// PreviewImage is an image in multiple resolutions.
+type PreviewImage struct {
+	ID string `json:"id"`
+	// Source is the image in its original resolution.
+	Source      Image   `json:"source"`
+	Resolutions []Image `json:"resolutions"`
+	// Variants are other versions of the image, i.e. "gif", "mp4", "nsfw" (blurred) or "obfuscated".
+	Variants map[string]PreviewVariant `json:"variants"`
+}
+
+// PreviewVariant is a version of a PreviewImage.
+type PreviewVariant struct {
+	Source      Image   `json:"source"`
+	Resolutions []Image `json:"resolutions"`
+}
+
+// GalleryData lists the items of a gallery post in order. The files are in Post.MediaMetadata.
+type GalleryData struct {
+	Items []GalleryItem `json:"items"`
+}
+
+// GalleryItem is an item of a gallery post.
+type GalleryItem struct {
+	ID int64 `json:"id"`
+	// MediaID is the key of the file in Post.MediaMetadata.
+	MediaID     string `json:"media_id"`
+	Caption     string `json:"caption"`
+	OutboundURL string `json:"outbound_url"`
+}
+
+// MediaMetadata describes a file of a gallery post, or an image embedded in a text post or comment.
+type MediaMetadata struct {
+	ID string `json:"id"`
+	// Status is "valid" once reddit processed the file, "unprocessed" before & "failed" if it couldn't.
+	Status string `json:"status"`
+	// Type is one of "Image", "AnimatedImage" & "RedditVideo".
+	Type     string `json:"e"`
+	MimeType string `json:"m"`
+	// Source is the file in its original resolution, Previews are smaller versions.
+	Source   MediaSource   `json:"s"`
+	Previews []MediaSource `json:"p"`
+	// DashURL, HLSURL & IsGIF are only set for videos.
+	DashURL string `json:"dashUrl"`
+	HLSURL  string `json:"hlsUrl"`
+	IsGIF   bool   `json:"isGif"`
+}
+
+// MediaSource is a version of a file in MediaMetadata. Images have URL set, animated images GIF & MP4.
+type MediaSource struct {
+	URL    string `json:"u"`
+	GIF    string `json:"gif"`
+	MP4    string `json:"mp4"`
+	Width  uint   `json:"x"`
+	Height uint   `json:"y"`
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMediaURLs(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		json string
+		// media, gallery, preview & video are the results of MediaURLs, GalleryURLs, PreviewURL & VideoURL
+		media   []string
+		gallery []string
+		preview string
+		video   string
+	}{
+		{name: "no media", json: `{"name": "t3_a", "is_self": true, "selftext": "hello"}`},
+		{
+			// media_metadata is a map, the order is only in gallery_data
+			name: "gallery",
+			json: `{"name": "t3_a", "is_gallery": true,
+				"gallery_data": {"items": [{"media_id": "mmm", "id": 1}, {"media_id": "zzz", "id": 2}, {"media_id": "aaa", "id": 3}]},
+				"media_metadata": {
+					"aaa": {"status": "valid", "e": "Image", "m": "image/png", "s": {"u": "https://preview.redd.it/aaa.png?width=10&amp;s=1", "x": 10, "y": 10}},
+					"mmm": {"status": "valid", "e": "AnimatedImage", "m": "image/gif", "s": {"gif": "https://i.redd.it/mmm.gif", "mp4": "https://preview.redd.it/mmm.gif?format=mp4&amp;s=2"}},
+					"zzz": {"status": "valid", "e": "RedditVideo", "hlsUrl": "https://v.redd.it/zzz/HLSPlaylist.m3u8?a=1&amp;v=1"}
+				}}`,
+			media:   []string{"https://preview.redd.it/mmm.gif?format=mp4&s=2", "https://v.redd.it/zzz/HLSPlaylist.m3u8?a=1&v=1", "https://preview.redd.it/aaa.png?width=10&s=1"},
+			gallery: []string{"https://preview.redd.it/mmm.gif?format=mp4&s=2", "https://v.redd.it/zzz/HLSPlaylist.m3u8?a=1&v=1", "https://preview.redd.it/aaa.png?width=10&s=1"},
+		},
+		{
+			name: "gallery with unprocessed & failed items",
+			json: `{"name": "t3_a", "is_gallery": true,
+				"gallery_data": {"items": [{"media_id": "a"}, {"media_id": "b"}, {"media_id": "c"}, {"media_id": "missing"}]},
+				"media_metadata": {
+					"a": {"status": "unprocessed"},
+					"b": {"status": "valid", "e": "Image", "s": {"u": "https://i.redd.it/b.jpg"}},
+					"c": {"status": "failed"}
+				}}`,
+			media:   []string{"https://i.redd.it/b.jpg"},
+			gallery: []string{"https://i.redd.it/b.jpg"},
+		},
+		{
+			name: "image",
+			json: `{"name": "t3_a", "post_hint": "image", "url": "https://i.redd.it/a.jpg",
+				"preview": {"images": [{"source": {"url": "https://preview.redd.it/a.jpg?auto=webp&amp;s=1"}}]}}`,
+			media:   []string{"https://i.redd.it/a.jpg"},
+			preview: "https://preview.redd.it/a.jpg?auto=webp&s=1",
+		},
+		{
+			name: "preview without source",
+			json: `{"name": "t3_a", "post_hint": "link", "url": "https://example.com",
+				"preview": {"images": [{"resolutions": [
+					{"url": "https://external-preview.redd.it/a.jpg?width=108&amp;s=1"},
+					{"url": "https://external-preview.redd.it/a.jpg?width=640&amp;s=2"}
+				]}]}}`,
+			media:   []string{"https://external-preview.redd.it/a.jpg?width=640&s=2"},
+			preview: "https://external-preview.redd.it/a.jpg?width=640&s=2",
+		},
+		{
+			name: "video",
+			json: `{"name": "t3_a", "is_video": true, "post_hint": "hosted:video",
+				"secure_media": {"reddit_video": {"fallback_url": "https://v.redd.it/a/DASH_720.mp4?source=fallback&amp;x=1"}},
+				"preview": {"images": [{"source": {"url": "https://preview.redd.it/a.png"}}]}}`,
+			media:   []string{"https://v.redd.it/a/DASH_720.mp4?source=fallback&x=1"},
+			preview: "https://preview.redd.it/a.png",
+			video:   "https://v.redd.it/a/DASH_720.mp4?source=fallback&x=1",
+		},
+		{
+			name: "gif converted to video",
+			json: `{"name": "t3_a", "post_hint": "link", "url": "https://example.com/a.gif",
+				"preview": {"images": [{"source": {"url": "https://external-preview.redd.it/a.png"}}],
+				"reddit_video_preview": {"fallback_url": "https://v.redd.it/b/DASH_480.mp4", "is_gif": true}}}`,
+			media:   []string{"https://v.redd.it/b/DASH_480.mp4"},
+			preview: "https://external-preview.redd.it/a.png",
+			video:   "https://v.redd.it/b/DASH_480.mp4",
+		},
+		{
+			// images embedded in text posts have no order, they are sorted by ID
+			name: "text post with images",
+			json: `{"name": "t3_a", "is_self": true,
+				"media_metadata": {
+					"b": {"status": "valid", "e": "Image", "s": {"u": "https://preview.redd.it/b.png?width=1&amp;s=1"}},
+					"c": {"status": "failed"},
+					"a": {"status": "valid", "e": "Image", "s": {"u": "https://preview.redd.it/a.png"}}
+				}}`,
+			media: []string{"https://preview.redd.it/a.png", "https://preview.redd.it/b.png?width=1&s=1"},
+		},
+		{
+			name: "crosspost of a gallery",
+			json: `{"name": "t3_b", "crosspost_parent": "t3_a", "url": "/r/test/comments/a/",
+				"crosspost_parent_list": [{"name": "t3_a", "is_gallery": true,
+					"gallery_data": {"items": [{"media_id": "y"}, {"media_id": "x"}]},
+					"media_metadata": {
+						"x": {"status": "valid", "e": "Image", "s": {"u": "https://i.redd.it/x.jpg"}},
+						"y": {"status": "valid", "e": "Image", "s": {"u": "https://i.redd.it/y.jpg"}}
+					}}]}`,
+			media:   []string{"https://i.redd.it/y.jpg", "https://i.redd.it/x.jpg"},
+			gallery: []string{"https://i.redd.it/y.jpg", "https://i.redd.it/x.jpg"},
+		},
+		{
+			name: "crosspost of a video",
+			json: `{"name": "t3_b", "crosspost_parent": "t3_a",
+				"crosspost_parent_list": [{"name": "t3_a", "is_video": true,
+					"media": {"reddit_video": {"fallback_url": "https://v.redd.it/a/DASH_360.mp4"}}}]}`,
+			media: []string{"https://v.redd.it/a/DASH_360.mp4"},
+			video: "https://v.redd.it/a/DASH_360.mp4",
+		},
+		{
+			// the crosspost has a preview of its own, so the parent isn't needed
+			name: "crosspost with preview",
+			json: `{"name": "t3_b", "crosspost_parent": "t3_a",
+				"preview": {"images": [{"source": {"url": "https://preview.redd.it/b.jpg"}}]},
+				"crosspost_parent_list": [{"name": "t3_a", "is_self": true,
+					"media_metadata": {"a": {"status": "valid", "e": "Image", "s": {"u": "https://preview.redd.it/a.png"}}}}]}`,
+			media:   []string{"https://preview.redd.it/b.jpg"},
+			preview: "https://preview.redd.it/b.jpg",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var p Post
+			if err := json.Unmarshal([]byte(tc.json), &p); err != nil {
+				t.Fatal(err)
+			}
+			if got := p.MediaURLs(); strings.Join(got, " ") != strings.Join(tc.media, " ") {
+				t.Errorf("MediaURLs() = %q, want %q", got, tc.media)
+			}
+			if got := p.GalleryURLs(); strings.Join(got, " ") != strings.Join(tc.gallery, " ") {
+				t.Errorf("GalleryURLs() = %q, want %q", got, tc.gallery)
+			}
+			if got := p.PreviewURL(); got != tc.preview {
+				t.Errorf("PreviewURL() = %q, want %q", got, tc.preview)
+			}
+			if got := p.VideoURL(); got != tc.video {
+				t.Errorf("VideoURL() = %q, want %q", got, tc.video)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// GetID returns the RedditID of the Message
+func (m Message) GetID() RedditID { return m.Name }
+
+// CreatedAt returns the time.Time the message was sent at
+func (m Message) CreatedAt() time.Time { return time.Unix(int64(m.CreatedUTC), 0) }
+
+// GetURL returns a link to the message, or to the comment for comment replies & mentions
+func (m Message) GetURL() string {
+	if m.WasComment {
+		return fmt.Sprintf("https://www.reddit.com%s", m.Context)
+	}
+	return fmt.Sprintf("https://www.reddit.com/message/messages/%s", m.ID)
+}
+
+// GetAuthor returns the name of the Message Author
+func (m Message) GetAuthor() string { return m.Author }
+
+// GetBody returns the content of the Message in Markdown
+func (m Message) GetBody() string { return m.Body }
+
+// IsUnread tells you if the message hasn't been read yet
+func (m Message) IsUnread() bool { return m.New }
+
+// IsPrivate tells you if the message is a private message, and not a comment reply or mention
+func (m Message) IsPrivate() bool { return !m.WasComment }
+
+// UnmarshalJSON parses the replies of a message. Reddit sends an empty string
+// if there are no replies, and a Listing otherwise.
+func (r *MessageReplies) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte(`""`)) || bytes.Equal(data, []byte("null")) {
+		*r = nil
+		return nil
+	}
+	resp := &Response{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return err
+	}
+	list, ok := resp.Data.(*Listing)
+	if !ok {
+		return fmt.Errorf("couldn't convert replies to Listing struct. Data has Kind '%s'", resp.Kind)
+	}
+	*r = nil
+	for _, child := range list.Children {
+		if m, ok := child.Data.(*Message); ok {
+			*r = append(*r, m)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON converts MessageReplies back into the format used by reddit.
+func (r MessageReplies) MarshalJSON() ([]byte, error) {
+	if len(r) == 0 {
+		return []byte(`""`), nil
+	}
+	children := make([]RedditElement, 0, len(r))
+	for _, m := range r {
+		children = append(children, RedditElement{Kind: KMessage, Data: m})
+	}
+	return json.Marshal(struct {
+		Kind responseType `json:"kind"`
+		Data Listing      `json:"data"`
+	}{rListing, Listing{Children: children}})
+}
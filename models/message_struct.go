@@ -0,0 +1,44 @@
+package models
+
+// MessageType tells you what an inbox item is about
+type MessageType string
+
+// Types of inbox items
+const (
+	MessageTypePrivate         MessageType = "unknown"
+	MessageTypeCommentReply    MessageType = "comment_reply"
+	MessageTypePostReply       MessageType = "post_reply"
+	MessageTypeUsernameMention MessageType = "username_mention"
+)
+
+// Message defines a private message (t4_XXXXX) or another item in the inbox.
+// Comment replies, post replies & username mentions are sent by reddit as comments (t1_XXXXX),
+// but with the fields of a message. WasComment is true for those.
+type Message struct {
+	ID                    string         `json:"id"`
+	Name                  RedditID       `json:"name"`
+	Author                string         `json:"author"`
+	AuthorFullname        RedditID       `json:"author_fullname"`
+	Dest                  string         `json:"dest"`
+	Subject               string         `json:"subject"`
+	Body                  string         `json:"body"`
+	BodyHTML              string         `json:"body_html"`
+	Type                  MessageType    `json:"type"`
+	WasComment            bool           `json:"was_comment"`
+	New                   bool           `json:"new"`
+	Context               string         `json:"context"`
+	LinkTitle             string         `json:"link_title"`
+	ParentID              RedditID       `json:"parent_id"`
+	FirstMessageName      RedditID       `json:"first_message_name"`
+	Replies               MessageReplies `json:"replies"`
+	Subreddit             string         `json:"subreddit"`
+	SubredditNamePrefixed string         `json:"subreddit_name_prefixed"`
+	Distinguished         string         `json:"distinguished"`
+	Score                 int            `json:"score"`
+	NumComments           int            `json:"num_comments"`
+	Created               float64        `json:"created"`
+	CreatedUTC            float64        `json:"created_utc"`
+}
+
+// MessageReplies are the replies to a private message
+type MessageReplies []*Message
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInboxListing(t *testing.T) {
+	// the inbox sends replies & mentions as t1, with was_comment set. Comments elsewhere don't have it.
+	data := `{"kind": "Listing", "data": {"children": [
+		{"kind": "t1", "data": {"name": "t1_a", "author": "alice", "body": "reply", "was_comment": true, "type": "comment_reply", "new": true}},
+		{"kind": "t4", "data": {"name": "t4_b", "author": "bob", "subject": "hi", "body": "message", "was_comment": false}},
+		{"kind": "t1", "data": {"name": "t1_c", "author": "carol", "body": "comment", "link_id": "t3_x"}}
+	], "after": null}}`
+	resp := &Response{}
+	if err := json.Unmarshal([]byte(data), resp); err != nil {
+		t.Fatal(err)
+	}
+	list, ok := resp.Data.(*Listing)
+	if !ok || len(list.Children) != 3 {
+		t.Fatalf("got %T, want a Listing with 3 children", resp.Data)
+	}
+	if m, ok := list.Children[0].Data.(*Message); !ok || m.Name != "t1_a" || !m.WasComment || m.Type != MessageTypeCommentReply || !m.New {
+		t.Errorf("got %#v, want the comment reply as *Message", list.Children[0].Data)
+	}
+	if m, ok := list.Children[1].Data.(*Message); !ok || m.Name != "t4_b" || m.Subject != "hi" {
+		t.Errorf("got %#v, want the private message as *Message", list.Children[1].Data)
+	}
+	if c, ok := list.Children[2].Data.(*Comment); !ok || c.Name != "t1_c" || c.LinkID != "t3_x" {
+		t.Errorf("got %#v, want the comment as *Comment", list.Children[2].Data)
+	}
+}
@@ -15,3 +15,17 @@ func (m ModAction) CreatedAt() time.Time { return time.Unix(int64(m.CreatedUTC),
 func (m ModAction) GetURL() string {
 	return fmt.Sprintf("https://www.reddit.com/%s", m.TargetPermalink)
 }
+
+// IsRemoval tells you if the action removed a post or comment
+func (t ModActionType) IsRemoval() bool {
+	switch t {
+	case ModActionRemoveLink, ModActionSpamLink, ModActionRemoveComment, ModActionSpamComment:
+		return true
+	}
+	return false
+}
+
+// IsApproval tells you if the action approved a post or comment
+func (t ModActionType) IsApproval() bool {
+	return t == ModActionApproveLink || t == ModActionApproveComment
+}
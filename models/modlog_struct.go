@@ -2,19 +2,87 @@ package models
 
 // ModAction has details about stuff a moderator did. Usually bad stuff.
 type ModAction struct {
-	Description           string   `json:"description"`
-	TargetBody            string   `json:"target_body"`
-	ModID36               string   `json:"mod_id36"`
-	CreatedUTC            float64  `json:"created_utc"`
-	Subreddit             string   `json:"subreddit"`
-	TargetTitle           string   `json:"target_title"`
-	TargetPermalink       string   `json:"target_permalink"`
-	SubredditNamePrefixed string   `json:"subreddit_name_prefixed"`
-	Details               string   `json:"details"`
-	Action                string   `json:"action"`
-	TargetAuthor          string   `json:"target_author"`
-	TargetFullname        RedditID `json:"target_fullname"`
-	SrID36                string   `json:"sr_id36"`
-	ID                    string   `json:"id"`
-	Mod                   string   `json:"mod"`
+	Description           string        `json:"description"`
+	TargetBody            string        `json:"target_body"`
+	ModID36               string        `json:"mod_id36"`
+	CreatedUTC            float64       `json:"created_utc"`
+	Subreddit             string        `json:"subreddit"`
+	TargetTitle           string        `json:"target_title"`
+	TargetPermalink       string        `json:"target_permalink"`
+	SubredditNamePrefixed string        `json:"subreddit_name_prefixed"`
+	Details               string        `json:"details"`
+	Action                ModActionType `json:"action"`
+	TargetAuthor          string        `json:"target_author"`
+	TargetFullname        RedditID      `json:"target_fullname"`
+	SrID36                string        `json:"sr_id36"`
+	ID                    string        `json:"id"`
+	Mod                   string        `json:"mod"`
 }
+
+// ModActionType is the type of a ModAction. reddit adds new types from time to time,
+// so don't expect every ModAction to have one of the types below.
+type ModActionType string
+
+// Known types of mod actions
+const (
+	ModActionBanUser                ModActionType = "banuser"
+	ModActionUnbanUser              ModActionType = "unbanuser"
+	ModActionMuteUser               ModActionType = "muteuser"
+	ModActionUnmuteUser             ModActionType = "unmuteuser"
+	ModActionSpamLink               ModActionType = "spamlink"
+	ModActionRemoveLink             ModActionType = "removelink"
+	ModActionApproveLink            ModActionType = "approvelink"
+	ModActionSpamComment            ModActionType = "spamcomment"
+	ModActionRemoveComment          ModActionType = "removecomment"
+	ModActionApproveComment         ModActionType = "approvecomment"
+	ModActionShowComment            ModActionType = "showcomment"
+	ModActionDistinguish            ModActionType = "distinguish"
+	ModActionSticky                 ModActionType = "sticky"
+	ModActionUnsticky               ModActionType = "unsticky"
+	ModActionLock                   ModActionType = "lock"
+	ModActionUnlock                 ModActionType = "unlock"
+	ModActionMarkNSFW               ModActionType = "marknsfw"
+	ModActionSpoiler                ModActionType = "spoiler"
+	ModActionUnspoiler              ModActionType = "unspoiler"
+	ModActionMarkOriginalContent    ModActionType = "markoriginalcontent"
+	ModActionIgnoreReports          ModActionType = "ignorereports"
+	ModActionUnignoreReports        ModActionType = "unignorereports"
+	ModActionSetSuggestedSort       ModActionType = "setsuggestedsort"
+	ModActionSetContestMode         ModActionType = "setcontestmode"
+	ModActionUnsetContestMode       ModActionType = "unsetcontestmode"
+	ModActionEditFlair              ModActionType = "editflair"
+	ModActionEditSettings           ModActionType = "editsettings"
+	ModActionCreateRule             ModActionType = "createrule"
+	ModActionEditRule               ModActionType = "editrule"
+	ModActionReorderRules           ModActionType = "reorderrules"
+	ModActionDeleteRule             ModActionType = "deleterule"
+	ModActionAddModerator           ModActionType = "addmoderator"
+	ModActionInviteModerator        ModActionType = "invitemoderator"
+	ModActionUninviteModerator      ModActionType = "uninvitemoderator"
+	ModActionAcceptModeratorInvite  ModActionType = "acceptmoderatorinvite"
+	ModActionRemoveModerator        ModActionType = "removemoderator"
+	ModActionSetPermissions         ModActionType = "setpermissions"
+	ModActionAddContributor         ModActionType = "addcontributor"
+	ModActionRemoveContributor      ModActionType = "removecontributor"
+	ModActionWikiRevise             ModActionType = "wikirevise"
+	ModActionWikiPermLevel          ModActionType = "wikipermlevel"
+	ModActionWikiPageListed         ModActionType = "wikipagelisted"
+	ModActionWikiBanned             ModActionType = "wikibanned"
+	ModActionWikiUnbanned           ModActionType = "wikiunbanned"
+	ModActionWikiContributor        ModActionType = "wikicontributor"
+	ModActionRemoveWikiContributor  ModActionType = "removewikicontributor"
+	ModActionModmailEnrollment      ModActionType = "modmail_enrollment"
+	ModActionCreateRemovalReason    ModActionType = "createremovalreason"
+	ModActionUpdateRemovalReason    ModActionType = "updateremovalreason"
+	ModActionDeleteRemovalReason    ModActionType = "deleteremovalreason"
+	ModActionAddNote                ModActionType = "addnote"
+	ModActionDeleteNote             ModActionType = "deletenote"
+	ModActionCollections            ModActionType = "collections"
+	ModActionEvents                 ModActionType = "events"
+	ModActionCreateScheduledPost    ModActionType = "create_scheduled_post"
+	ModActionEditScheduledPost      ModActionType = "edit_scheduled_post"
+	ModActionDeleteScheduledPost    ModActionType = "delete_scheduled_post"
+	ModActionSubmitScheduledPost    ModActionType = "submit_scheduled_post"
+	ModActionEditPostRequirements   ModActionType = "edit_post_requirements"
+	ModActionAdjustPostCrowdControl ModActionType = "adjust_post_crowd_control_level"
+)
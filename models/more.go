@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// GetID returns the RedditID of the More placeholder
+func (m More) GetID() RedditID { return m.Name }
+
+// CreatedAt returns a zero time.Time, since placeholders are not created by anyone
+func (m More) CreatedAt() time.Time { return time.Time{} }
+
+// GetURL returns an empty string, since placeholders have no URL
+func (m More) GetURL() string { return "" }
+
+// IsContinueThread tells you if the placeholder is a "continue this thread" link
+func (m More) IsContinueThread() bool { return m.Count == 0 && len(m.Children) == 0 }
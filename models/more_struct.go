@@ -0,0 +1,13 @@
+package models
+
+// More is a placeholder for comments reddit didn't include in a comment listing ("load more comments").
+// Children contains the IDs (without prefix) of the missing comments. If Count is 0 and Children is
+// empty, it stands for a "continue this thread" link, and the replies to ParentID have to be fetched separately.
+type More struct {
+	Count    int      `json:"count"`
+	Name     RedditID `json:"name"`
+	ID       string   `json:"id"`
+	ParentID RedditID `json:"parent_id"`
+	Depth    int      `json:"depth"`
+	Children []string `json:"children"`
+}
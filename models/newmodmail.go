@@ -0,0 +1,40 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// GetID returns the ID of the modmail conversation. Note that it isn't a fullname.
+func (m NewModmailConversation) GetID() RedditID { return RedditID(m.Conversation.ID) }
+
+// CreatedAt returns the time the conversation was last updated, which is what modmail is sorted by.
+func (m NewModmailConversation) CreatedAt() time.Time {
+	if m.Conversation.LastUpdated == nil {
+		return time.Time{}
+	}
+	return *m.Conversation.LastUpdated
+}
+
+// GetURL returns the link to the conversation
+func (m NewModmailConversation) GetURL() string {
+	return "https://mod.reddit.com/mail/all/" + m.Conversation.ID
+}
+
+// SortedMessages returns the messages of the conversation, oldest first.
+func (m NewModmailConversation) SortedMessages() []ModmailMessage {
+	ret := make([]ModmailMessage, 0, len(m.Messages))
+	for _, msg := range m.Messages {
+		ret = append(ret, msg)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Date.Equal(ret[j].Date) {
+			return ret[i].ID < ret[j].ID
+		}
+		return ret[i].Date.Before(ret[j].Date)
+	})
+	return ret
+}
+
+// IsArchived tells you if the conversation has been archived
+func (c ModmailConversation) IsArchived() bool { return c.State == ModmailStateArchived }
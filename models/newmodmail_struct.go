@@ -1,110 +1,190 @@
 package models
 
-import (
-	"encoding/json"
-	"time"
-)
+import "time"
 
 // NewModmailConversation represents a conversation between a user & mods
 // in the new modmail interface.
 type NewModmailConversation struct {
-	Conversation struct {
-		IsAuto bool `json:"isAuto"`
-		ObjIds []struct {
-			ID  string `json:"id"`
-			Key string `json:"key"`
-		} `json:"objIds"`
-		IsRepliable    bool       `json:"isRepliable"`
-		LastUserUpdate *time.Time `json:"lastUserUpdate"`
-		IsInternal     bool       `json:"isInternal"`
-		LastModUpdate  *time.Time `json:"lastModUpdate"`
-		LastUpdated    *time.Time `json:"lastUpdated"`
-		Authors        []struct {
-			IsMod         bool     `json:"isMod"`
-			IsAdmin       bool     `json:"isAdmin"`
-			Name          string   `json:"name"`
-			IsOp          bool     `json:"isOp"`
-			IsParticipant bool     `json:"isParticipant"`
-			IsHidden      bool     `json:"isHidden"`
-			ID            RedditID `json:"id"`
-			IsDeleted     bool     `json:"isDeleted"`
-		} `json:"authors"`
-		Owner struct {
-			DisplayName string   `json:"displayName"`
-			Type        string   `json:"type"`
-			ID          RedditID `json:"id"`
-		} `json:"owner"`
-		ID            string          `json:"id"`
-		IsHighlighted bool            `json:"isHighlighted"`
-		Subject       string          `json:"subject"`
-		Participant   json.RawMessage `json:"participant"`
-		State         int             `json:"state"`
-		LastUnread    *time.Time      `json:"lastUnread"`
-		NumMessages   int             `json:"numMessages"`
-	} `json:"conversation"`
-	Messages map[string]struct {
-		Body   string `json:"body"`
-		Author struct {
-			IsMod         bool     `json:"isMod"`
-			IsAdmin       bool     `json:"isAdmin"`
-			Name          string   `json:"name"`
-			IsOp          bool     `json:"isOp"`
-			IsParticipant bool     `json:"isParticipant"`
-			IsHidden      bool     `json:"isHidden"`
-			ID            RedditID `json:"id"`
-			IsDeleted     bool     `json:"isDeleted"`
-		} `json:"author"`
-		IsInternal   bool      `json:"isInternal"`
-		Date         time.Time `json:"date"`
-		BodyMarkdown string    `json:"bodyMarkdown"`
-		ID           string    `json:"id"`
-	} `json:"messages"`
-	User struct {
-		RecentComments map[RedditID]struct {
-			Comment   string    `json:"comment"`
-			Date      time.Time `json:"date"`
-			Permalink string    `json:"permalink"`
-			Title     string    `json:"title"`
-		} `json:"recentComments"`
-		MuteStatus struct {
-			IsMuted bool       `json:"isMuted"`
-			EndDate *time.Time `json:"endDate"`
-			Reason  string     `json:"reason"`
-		} `json:"muteStatus"`
-		Name      string    `json:"name"`
-		Created   time.Time `json:"created"`
-		BanStatus struct {
-			EndDate     *time.Time `json:"endDate"`
-			Reason      string     `json:"reason"`
-			IsBanned    bool       `json:"isBanned"`
-			IsPermanent bool       `json:"isPermanent"`
-		} `json:"banStatus"`
-		IsSuspended    bool `json:"isSuspended"`
-		IsShadowBanned bool `json:"isShadowBanned"`
-		RecentPosts    map[RedditID]struct {
-			Date      time.Time `json:"date"`
-			Permalink string    `json:"permalink"`
-			Title     string    `json:"title"`
-		} `json:"recentPosts"`
-		RecentConvos map[string]struct {
-			Date      time.Time `json:"date"`
-			Permalink string    `json:"permalink"`
-			ID        string    `json:"id"`
-			Subject   string    `json:"subject"`
-		} `json:"recentConvos"`
-		ID RedditID `json:"id"`
-	} `json:"user"`
-	ModActions map[string]struct {
-		Date         time.Time `json:"date"`
-		ActionTypeID int       `json:"actionTypeId"`
-		ID           string    `json:"id"`
-		Author       struct {
-			Name      string   `json:"name"`
-			IsMod     bool     `json:"isMod"`
-			IsAdmin   bool     `json:"isAdmin"`
-			IsHidden  bool     `json:"isHidden"`
-			ID        RedditID `json:"id"`
-			IsDeleted bool     `json:"isDeleted"`
-		} `json:"author"`
-	} `json:"modActions"`
+	Conversation ModmailConversation `json:"conversation"`
+	// Messages & ModActions are keyed by their ID. Conversation.ObjIDs lists them in order.
+	Messages   map[string]ModmailMessage   `json:"messages"`
+	User       ModmailUser                 `json:"user"`
+	ModActions map[string]ModmailModAction `json:"modActions"`
+}
+
+// ModmailConversation contains the metadata of a modmail conversation.
+type ModmailConversation struct {
+	IsAuto bool `json:"isAuto"`
+	// ObjIDs lists the messages & mod actions of the conversation, oldest first.
+	ObjIDs         []ModmailObjID  `json:"objIds"`
+	IsRepliable    bool            `json:"isRepliable"`
+	LastUserUpdate *time.Time      `json:"lastUserUpdate"`
+	IsInternal     bool            `json:"isInternal"`
+	LastModUpdate  *time.Time      `json:"lastModUpdate"`
+	LastUpdated    *time.Time      `json:"lastUpdated"`
+	Authors        []ModmailAuthor `json:"authors"`
+	Owner          ModmailOwner    `json:"owner"`
+	ID             string          `json:"id"`
+	IsHighlighted  bool            `json:"isHighlighted"`
+	Subject        string          `json:"subject"`
+	// Participant is the non-moderator of the conversation. It is empty for internal conversations.
+	Participant ModmailAuthor            `json:"participant"`
+	State       ModmailConversationState `json:"state"`
+	LastUnread  *time.Time               `json:"lastUnread"`
+	NumMessages int                      `json:"numMessages"`
+}
+
+// ModmailObjID references a message (Key "messages") or mod action (Key "modActions") of a conversation.
+type ModmailObjID struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// ModmailAuthor is a participant of a modmail conversation.
+type ModmailAuthor struct {
+	IsMod         bool     `json:"isMod"`
+	IsAdmin       bool     `json:"isAdmin"`
+	Name          string   `json:"name"`
+	IsOp          bool     `json:"isOp"`
+	IsParticipant bool     `json:"isParticipant"`
+	IsHidden      bool     `json:"isHidden"`
+	ID            RedditID `json:"id"`
+	IsDeleted     bool     `json:"isDeleted"`
+}
+
+// ModmailOwner is the subreddit a modmail conversation belongs to.
+type ModmailOwner struct {
+	DisplayName string   `json:"displayName"`
+	Type        string   `json:"type"`
+	ID          RedditID `json:"id"`
+}
+
+// ModmailMessage is a message in a modmail conversation.
+type ModmailMessage struct {
+	Body   string        `json:"body"`
+	Author ModmailAuthor `json:"author"`
+	// IsInternal is set for private moderator notes.
+	IsInternal   bool      `json:"isInternal"`
+	Date         time.Time `json:"date"`
+	BodyMarkdown string    `json:"bodyMarkdown"`
+	ID           string    `json:"id"`
+}
+
+// ModmailUser contains information about the participant of a modmail conversation.
+type ModmailUser struct {
+	RecentComments map[RedditID]ModmailRecentComment `json:"recentComments"`
+	MuteStatus     ModmailMuteStatus                 `json:"muteStatus"`
+	Name           string                            `json:"name"`
+	Created        time.Time                         `json:"created"`
+	BanStatus      ModmailBanStatus                  `json:"banStatus"`
+	IsSuspended    bool                              `json:"isSuspended"`
+	IsShadowBanned bool                              `json:"isShadowBanned"`
+	RecentPosts    map[RedditID]ModmailRecentPost    `json:"recentPosts"`
+	RecentConvos   map[string]ModmailRecentConvo     `json:"recentConvos"`
+	ID             RedditID                          `json:"id"`
+}
+
+// ModmailRecentComment is a recent comment of the participant in the subreddit.
+type ModmailRecentComment struct {
+	Comment   string    `json:"comment"`
+	Date      time.Time `json:"date"`
+	Permalink string    `json:"permalink"`
+	Title     string    `json:"title"`
+}
+
+// ModmailRecentPost is a recent post of the participant in the subreddit.
+type ModmailRecentPost struct {
+	Date      time.Time `json:"date"`
+	Permalink string    `json:"permalink"`
+	Title     string    `json:"title"`
+}
+
+// ModmailRecentConvo is a recent modmail conversation of the participant with the subreddit.
+type ModmailRecentConvo struct {
+	Date      time.Time `json:"date"`
+	Permalink string    `json:"permalink"`
+	ID        string    `json:"id"`
+	Subject   string    `json:"subject"`
+}
+
+// ModmailMuteStatus tells you if the participant is muted in the subreddit.
+type ModmailMuteStatus struct {
+	IsMuted bool       `json:"isMuted"`
+	EndDate *time.Time `json:"endDate"`
+	Reason  string     `json:"reason"`
+}
+
+// ModmailBanStatus tells you if the participant is banned from the subreddit.
+type ModmailBanStatus struct {
+	EndDate     *time.Time `json:"endDate"`
+	Reason      string     `json:"reason"`
+	IsBanned    bool       `json:"isBanned"`
+	IsPermanent bool       `json:"isPermanent"`
+}
+
+// ModmailModAction is an action a moderator took in a modmail conversation, i.e. archiving it.
+type ModmailModAction struct {
+	Date         time.Time         `json:"date"`
+	ActionTypeID ModmailActionType `json:"actionTypeId"`
+	ID           string            `json:"id"`
+	Author       ModmailAuthor     `json:"author"`
+}
+
+// ModmailActionType is the type of a ModmailModAction.
+type ModmailActionType int
+
+// Types of ModmailModAction
+const (
+	ModmailActionHighlight   ModmailActionType = 0
+	ModmailActionUnhighlight ModmailActionType = 1
+	ModmailActionArchive     ModmailActionType = 2
+	ModmailActionUnarchive   ModmailActionType = 3
+	ModmailActionReported    ModmailActionType = 4
+	ModmailActionMute        ModmailActionType = 5
+	ModmailActionUnmute      ModmailActionType = 6
+	ModmailActionBan         ModmailActionType = 7
+	ModmailActionUnban       ModmailActionType = 8
+)
+
+// ModmailConversationState is the state of a ModmailConversation.
+type ModmailConversationState int
+
+// States of a ModmailConversation
+const (
+	ModmailStateNew          ModmailConversationState = 0
+	ModmailStateInProgress   ModmailConversationState = 1
+	ModmailStateArchived     ModmailConversationState = 2
+	ModmailStateAppeals      ModmailConversationState = 3
+	ModmailStateJoinRequests ModmailConversationState = 4
+	ModmailStateFiltered     ModmailConversationState = 5
+)
+
+// ModmailFilter selects the conversations returned when listing modmail.
+type ModmailFilter string
+
+// Filters for listing modmail
+const (
+	ModmailAll           ModmailFilter = "all"
+	ModmailNew           ModmailFilter = "new"
+	ModmailInProgress    ModmailFilter = "inprogress"
+	ModmailArchived      ModmailFilter = "archived"
+	ModmailHighlighted   ModmailFilter = "highlighted"
+	ModmailMod           ModmailFilter = "mod"
+	ModmailNotifications ModmailFilter = "notifications"
+	ModmailJoinRequests  ModmailFilter = "join_requests"
+	ModmailAppeals       ModmailFilter = "appeals"
+	ModmailFiltered      ModmailFilter = "filtered"
+)
+
+// ModmailUnreadCount is the number of unread modmail conversations per filter.
+type ModmailUnreadCount struct {
+	New           int `json:"new"`
+	InProgress    int `json:"inprogress"`
+	Archived      int `json:"archived"`
+	Highlighted   int `json:"highlighted"`
+	Mod           int `json:"mod"`
+	Notifications int `json:"notifications"`
+	JoinRequests  int `json:"join_requests"`
+	Appeals       int `json:"appeals"`
+	Filtered      int `json:"filtered"`
 }
@@ -50,6 +50,13 @@ func (p Post) GetURL() string { return p.URL }
 // GetCreated returns the creation date of the Post
 func (p Post) GetCreated() time.Time { return time.Unix(int64(p.CreatedUTC), 0) }
 
+// IsEdited tells you if the Post has been edited
+func (p Post) IsEdited() bool { return p.Edited.IsEdited }
+
+// EditedAt returns the time the Post was last edited. It is zero if it hasn't been edited,
+// or reddit didn't keep the time.
+func (p Post) EditedAt() time.Time { return p.Edited.At }
+
 // GetBanned returns the mod & time who deleted the Post
 func (p Post) GetBanned() SubModAction {
 	var mod string
@@ -27,88 +27,86 @@ type Post struct {
 	SubredditType              string              `json:"subreddit_type"`
 	Ups                        int                 `json:"ups"`
 	TotalAwardsReceived        uint                `json:"total_awards_received"`
-	MediaEmbed                 json.RawMessage     `json:"media_embed"` // is: []string
+	MediaEmbed                 MediaEmbed          `json:"media_embed"`
 	ThumbnailWidth             uint                `json:"thumbnail_width"`
 	AuthorFlairTemplateID      string              `json:"author_flair_template_id"`
 	IsOriginalContent          bool                `json:"is_original_content"`
 	UserReports                []UserReport        `json:"user_reports"`
-	SecureMedia                json.RawMessage     `json:"secure_media"`
-	// "secure_media": {"reddit_video": {"fallback_url": "https://v.redd.it/cxtki09yq1451/DASH_480?source=fallback", "height": 480, "width": 480, "scrubber_media_url": "https://v.redd.it/cxtki09yq1451/DASH_96", "dash_url": "https://v.redd.it/cxtki09yq1451/DASHPlaylist.mpd?a=1594672415%2CZjE0YThjNzI1NDNlMzkyYzRkZDAyYzZhY2E3NGE1ZTgwZTM0MjVhOWVkYzMxNDhkNzZiNzcwYzIxNmU0MDJjYg%3D%3D&amp;v=1&amp;f=sd", "duration": 6, "hls_url": "https://v.redd.it/cxtki09yq1451/HLSPlaylist.m3u8?a=1594672415%2CM2Q3YWI5MWRjZjE5ZDBkMTgxYTdkNjZhOTEyMjM4ODg1NjFiZDM4OGUwNjEwZTRkMjRmMzhhODlmNzYyODU5Ng%3D%3D&amp;v=1&amp;f=sd", "is_gif": false, "transcoding_status": "completed"}}
-	IsRedditMediaDomain bool                `json:"is_reddit_media_domain"`
-	IsMeta              bool                `json:"is_meta"`
-	Category            string              `json:"category"`
-	SecureMediaEmbed    json.RawMessage     `json:"secure_media_embed"` // is: []string
-	LinkFlairText       string              `json:"link_flair_text"`
-	CanModPost          bool                `json:"can_mod_post"`
-	Score               int                 `json:"score"`
-	ApprovedBy          string              `json:"approved_by"`
-	Thumbnail           string              `json:"thumbnail"`
-	Edited              json.RawMessage     `json:"edited"` // bool or float64
-	AuthorFlairCSSClass string              `json:"author_flair_css_class"`
-	AuthorFlairRichtext []map[string]string `json:"author_flair_richtext"`
-	Gildings            map[string]int      `json:"gildings"`
-	PostHint            string              `json:"post_hint"`
-	ContentCategories   []string            `json:"content_categories"`
-	IsSelf              bool                `json:"is_self"`
-	ModNote             string              `json:"mod_note"`
-	Created             float64             `json:"created"`
-	LinkFlairType       string              `json:"link_flair_type"`
-	BannedBy            json.RawMessage     `json:"banned_by"`
-	AuthorFlairType     string              `json:"author_flair_type"`
-	Domain              string              `json:"domain"`
-	SelftextHTML        string              `json:"selftext_html"`
-	Likes               bool                `json:"likes"`
-	SuggestedSort       string              `json:"suggested_sort"`
-	BannedAtUTC         float64             `json:"banned_at_utc"`
-	ViewCount           uint                `json:"view_count"`
-	Archived            bool                `json:"archived"`
-	NoFollow            bool                `json:"no_follow"`
-	IsCrosspostable     bool                `json:"is_crosspostable"`
-	Pinned              bool                `json:"pinned"`
-	Over18              bool                `json:"over_18"`
-	Preview             struct {
-		Images []struct {
-			Source      Image   `json:"source"`
-			Resolutions []Image `json:"resolutions"`
-			ID          string  `json:"id"`
-		} `json:"images"`
-		Enabled bool `json:"enabled"`
-	} `json:"preview"`
-	Awardings                []PostAward     `json:"all_awardings"`
-	MediaOnly                bool            `json:"media_only"`
-	CanGild                  bool            `json:"can_gild"`
-	Spoiler                  bool            `json:"spoiler"`
-	Locked                   bool            `json:"locked"`
-	AuthorFlairText          string          `json:"author_flair_text"`
-	Visited                  bool            `json:"visited"`
-	NumReports               int             `json:"num_reports"`
-	Distinguished            string          `json:"distinguished"`
-	SubredditID              RedditID        `json:"subreddit_id"`
-	ModReasonBy              string          `json:"mod_reason_by"`
-	RemovalReason            string          `json:"removal_reason"`
-	LinkFlairBackgroundColor string          `json:"link_flair_background_color"`
-	ID                       string          `json:"id"`
-	IsRobotIndexable         bool            `json:"is_robot_indexable"`
-	Author                   string          `json:"author"`
-	NumCrossposts            uint            `json:"num_crossposts"`
-	NumComments              uint            `json:"num_comments"`
-	SendReplies              bool            `json:"send_replies"`
-	WhitelistStatus          string          `json:"whitelist_status"`
-	ContestMode              bool            `json:"contest_mode"`
-	ModReports               []ModReport     `json:"mod_reports"`
-	AuthorPatreonFlair       bool            `json:"author_patreon_flair"`
-	AuthorFlairTextColor     string          `json:"author_flair_text_color"`
-	Permalink                string          `json:"permalink"`
-	ParentWhitelistStatus    string          `json:"parent_whitelist_status"`
-	Stickied                 bool            `json:"stickied"`
-	URL                      string          `json:"url"`
-	SubredditSubscribers     uint            `json:"subreddit_subscribers"`
-	CreatedUTC               float64         `json:"created_utc"`
-	Media                    json.RawMessage `json:"media"`
-	// "media": {"reddit_video": {"fallback_url": "https://v.redd.it/cxtki09yq1451/DASH_480?source=fallback", "height": 480, "width": 480, "scrubber_media_url": "https://v.redd.it/cxtki09yq1451/DASH_96", "dash_url": "https://v.redd.it/cxtki09yq1451/DASHPlaylist.mpd?a=1594672415%2CZjE0YThjNzI1NDNlMzkyYzRkZDAyYzZhY2E3NGE1ZTgwZTM0MjVhOWVkYzMxNDhkNzZiNzcwYzIxNmU0MDJjYg%3D%3D&amp;v=1&amp;f=sd", "duration": 6, "hls_url": "https://v.redd.it/cxtki09yq1451/HLSPlaylist.m3u8?a=1594672415%2CM2Q3YWI5MWRjZjE5ZDBkMTgxYTdkNjZhOTEyMjM4ODg1NjFiZDM4OGUwNjEwZTRkMjRmMzhhODlmNzYyODU5Ng%3D%3D&amp;v=1&amp;f=sd", "is_gif": false, "transcoding_status": "completed"}}
-	IsVideo  bool `json:"is_video"`
-	Approved bool `json:"approved"`
-	Removed  bool `json:"removed"`
+	SecureMedia                *Media              `json:"secure_media"`
+	IsRedditMediaDomain        bool                `json:"is_reddit_media_domain"`
+	IsMeta                     bool                `json:"is_meta"`
+	Category                   string              `json:"category"`
+	SecureMediaEmbed           MediaEmbed          `json:"secure_media_embed"`
+	LinkFlairText              string              `json:"link_flair_text"`
+	CanModPost                 bool                `json:"can_mod_post"`
+	Score                      int                 `json:"score"`
+	ApprovedBy                 string              `json:"approved_by"`
+	Thumbnail                  string              `json:"thumbnail"`
+	Edited                     Edited              `json:"edited"`
+	AuthorFlairCSSClass        string              `json:"author_flair_css_class"`
+	AuthorFlairRichtext        []map[string]string `json:"author_flair_richtext"`
+	Gildings                   map[string]int      `json:"gildings"`
+	PostHint                   string              `json:"post_hint"`
+	ContentCategories          []string            `json:"content_categories"`
+	IsSelf                     bool                `json:"is_self"`
+	ModNote                    string              `json:"mod_note"`
+	Created                    float64             `json:"created"`
+	LinkFlairType              string              `json:"link_flair_type"`
+	BannedBy                   json.RawMessage     `json:"banned_by"`
+	AuthorFlairType            string              `json:"author_flair_type"`
+	Domain                     string              `json:"domain"`
+	SelftextHTML               string              `json:"selftext_html"`
+	Likes                      bool                `json:"likes"`
+	SuggestedSort              string              `json:"suggested_sort"`
+	BannedAtUTC                float64             `json:"banned_at_utc"`
+	ViewCount                  uint                `json:"view_count"`
+	Archived                   bool                `json:"archived"`
+	NoFollow                   bool                `json:"no_follow"`
+	IsCrosspostable            bool                `json:"is_crosspostable"`
+	Pinned                     bool                `json:"pinned"`
+	Over18                     bool                `json:"over_18"`
+	Preview                    Preview             `json:"preview"`
+	Awardings                  []PostAward         `json:"all_awardings"`
+	MediaOnly                  bool                `json:"media_only"`
+	CanGild                    bool                `json:"can_gild"`
+	Spoiler                    bool                `json:"spoiler"`
+	Locked                     bool                `json:"locked"`
+	AuthorFlairText            string              `json:"author_flair_text"`
+	Visited                    bool                `json:"visited"`
+	NumReports                 int                 `json:"num_reports"`
+	Distinguished              string              `json:"distinguished"`
+	SubredditID                RedditID            `json:"subreddit_id"`
+	ModReasonBy                string              `json:"mod_reason_by"`
+	RemovalReason              string              `json:"removal_reason"`
+	LinkFlairBackgroundColor   string              `json:"link_flair_background_color"`
+	ID                         string              `json:"id"`
+	IsRobotIndexable           bool                `json:"is_robot_indexable"`
+	Author                     string              `json:"author"`
+	NumCrossposts              uint                `json:"num_crossposts"`
+	NumComments                uint                `json:"num_comments"`
+	SendReplies                bool                `json:"send_replies"`
+	WhitelistStatus            string              `json:"whitelist_status"`
+	ContestMode                bool                `json:"contest_mode"`
+	ModReports                 []ModReport         `json:"mod_reports"`
+	AuthorPatreonFlair         bool                `json:"author_patreon_flair"`
+	AuthorFlairTextColor       string              `json:"author_flair_text_color"`
+	Permalink                  string              `json:"permalink"`
+	ParentWhitelistStatus      string              `json:"parent_whitelist_status"`
+	Stickied                   bool                `json:"stickied"`
+	URL                        string              `json:"url"`
+	SubredditSubscribers       uint                `json:"subreddit_subscribers"`
+	CreatedUTC                 float64             `json:"created_utc"`
+	Media                      *Media              `json:"media"`
+	IsVideo                    bool                `json:"is_video"`
+	// IsGallery is set for gallery posts. Their items are listed in GalleryData, and the files are in MediaMetadata.
+	IsGallery     bool                     `json:"is_gallery"`
+	GalleryData   *GalleryData             `json:"gallery_data"`
+	MediaMetadata map[string]MediaMetadata `json:"media_metadata"`
+	// CrosspostParent is the post this one crossposted, which is also the first entry of CrosspostParentList.
+	CrosspostParent     RedditID `json:"crosspost_parent"`
+	CrosspostParentList []*Post  `json:"crosspost_parent_list"`
+	Approved            bool     `json:"approved"`
+	Removed             bool     `json:"removed"`
 }
 
 // PostAward defines a post "hipster gilding"
@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
 	"time"
@@ -41,6 +42,42 @@ func (r *RedditID) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("Unknown type for RedditID %v", t)
 }
 
+// Edited tells you if & when a post or comment has been edited.
+// reddit sends false for items that haven't been edited, and the time of the last edit otherwise.
+type Edited struct {
+	IsEdited bool
+	// At is the time of the last edit. It is zero for old edits reddit didn't keep the time of.
+	At time.Time
+}
+
+// UnmarshalJSON defines an Unmarshaller for Edited, accepting both a bool & a timestamp.
+func (e *Edited) UnmarshalJSON(data []byte) error {
+	var t interface{}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	switch v := t.(type) {
+	case bool:
+		*e = Edited{IsEdited: v}
+	case float64:
+		sec, frac := math.Modf(v)
+		*e = Edited{IsEdited: true, At: time.Unix(int64(sec), int64(frac*1e9))}
+	case nil:
+		*e = Edited{}
+	default:
+		return fmt.Errorf("Unknown type for Edited %v", t)
+	}
+	return nil
+}
+
+// MarshalJSON encodes Edited the way reddit does.
+func (e Edited) MarshalJSON() ([]byte, error) {
+	if !e.IsEdited || e.At.IsZero() {
+		return json.Marshal(e.IsEdited)
+	}
+	return json.Marshal(float64(e.At.UnixNano()) / 1e9)
+}
+
 // Type returns the RedditKind to a RedditID
 func (r RedditID) Type() RedditKind {
 	s := string(r)
@@ -76,15 +113,19 @@ const (
 	KSubreddit RedditKind = "t5"
 	KAward     RedditKind = "t6"
 	KModAction RedditKind = "modaction"
+	KMore      RedditKind = "more"
 	KUnknown   RedditKind = "tX"
 )
 
 type responseType string
 
 const (
-	rListing    responseType = "Listing"
-	rWiki       responseType = "wikipage"
-	rStylesheet responseType = "stylesheet"
+	rListing          responseType = "Listing"
+	rWiki             responseType = "wikipage"
+	rWikiPageListing  responseType = "wikipagelisting"
+	rWikiPageSettings responseType = "wikipagesettings"
+	rStylesheet       responseType = "stylesheet"
+	rUserList         responseType = "UserList"
 )
 
 // Response is a reply from the reddit API.
@@ -111,8 +152,14 @@ func (r *Response) UnmarshalJSON(data []byte) error {
 		r.Data = &Listing{}
 	case rWiki:
 		r.Data = &Wiki{}
+	case rWikiPageListing:
+		r.Data = &[]string{}
+	case rWikiPageSettings:
+		r.Data = &WikiSettings{}
 	case rStylesheet:
 		r.Data = &Stylesheet{}
+	case rUserList:
+		r.Data = &UserList{}
 	default:
 		return fmt.Errorf("%q is an invalid ResponseType", m.Kind)
 	}
@@ -143,6 +190,8 @@ type Submission interface {
 	GetScore() int
 	IsSticky() bool
 	GetCreated() time.Time
+	IsEdited() bool
+	EditedAt() time.Time
 	// Mod Stuff
 	IsRemoved() bool
 	IsApproved() bool
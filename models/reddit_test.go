@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEdited(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		json   string
+		edited bool
+		at     time.Time
+		// out is the JSON the value is encoded to again
+		out string
+	}{
+		{name: "not edited", json: `false`, out: `false`},
+		{name: "edited without time", json: `true`, edited: true, out: `true`},
+		{name: "timestamp", json: `1700000000`, edited: true, at: time.Unix(1700000000, 0), out: `1700000000`},
+		{name: "fractional timestamp", json: `1700000000.5`, edited: true, at: time.Unix(1700000000, 5e8), out: `1700000000.5`},
+		{name: "null", json: `null`, out: `false`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var p Post
+			if err := json.Unmarshal([]byte(`{"name": "t3_a", "edited": `+tc.json+`}`), &p); err != nil {
+				t.Fatal(err)
+			}
+			var c Comment
+			if err := json.Unmarshal([]byte(`{"name": "t1_a", "edited": `+tc.json+`}`), &c); err != nil {
+				t.Fatal(err)
+			}
+			for _, sub := range []Submission{p, c} {
+				if sub.IsEdited() != tc.edited || !sub.EditedAt().Equal(tc.at) {
+					t.Errorf("%s: got edited %t at %s, want %t at %s", sub.GetID(), sub.IsEdited(), sub.EditedAt(), tc.edited, tc.at)
+				}
+			}
+
+			out, err := json.Marshal(p.Edited)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tc.out {
+				t.Errorf("encoded to %s, want %s", out, tc.out)
+			}
+		})
+	}
+
+	var e Edited
+	if err := json.Unmarshal([]byte(`"yesterday"`), &e); err == nil {
+		t.Error("decoding a string succeeded")
+	}
+}
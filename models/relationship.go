@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// GetID returns the ID of the Relationship
+func (r Relationship) GetID() RedditID { return r.RelID }
+
+// CreatedAt returns time.Time the redditor was added to the list
+func (r Relationship) CreatedAt() time.Time { return time.Unix(int64(r.Date), 0) }
+
+// GetURL returns the link to the profile of the redditor
+func (r Relationship) GetURL() string { return "https://www.reddit.com/user/" + r.Name }
+
+// IsPermanent tells you if the Relationship has no end date, i.e. a permanent ban
+func (r Relationship) IsPermanent() bool { return r.DaysLeft == nil }
+
+// HasPermission tells you if a moderator has the given permission, either directly or through "all"
+func (r Relationship) HasPermission(perm ModPermission) bool {
+	for _, p := range r.ModPermissions {
+		if p == perm || p == ModPermAll {
+			return true
+		}
+	}
+	return false
+}
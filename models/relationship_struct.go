@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// UserList is a list of redditors with a relationship to a subreddit, i.e. banned users or moderators.
+type UserList struct {
+	Children []*Relationship `json:"children"`
+	After    string          `json:"after"`
+	Before   string          `json:"before"`
+}
+
+// Relationship is an entry in one of the user lists of a subreddit: banned, muted, wiki banned,
+// contributors or moderators.
+type Relationship struct {
+	// RelID identifies the entry, i.e. "rb_XXXXX". It is also the cursor used for paging.
+	RelID  RedditID `json:"rel_id"`
+	UserID RedditID `json:"id"`
+	Name   string   `json:"name"`
+	// Date is when the redditor was added to the list.
+	Date float64 `json:"date"`
+	// Note is the reason of bans & mutes, as seen by the moderators.
+	Note string `json:"note"`
+	// DaysLeft is the number of days until a temporary ban ends. It is nil for permanent bans.
+	DaysLeft *int `json:"days_left"`
+	// Expires is an approximation of when a temporary ban ends: DaysLeft added to the time the list
+	// was fetched, so it can be off by up to a day. It is zero if DaysLeft is nil.
+	Expires time.Time `json:"-"`
+	// ModPermissions of moderators & invited moderators.
+	ModPermissions []ModPermission `json:"mod_permissions"`
+}
+
+// ModPermission is a permission of a moderator.
+type ModPermission string
+
+// Known moderator permissions
+const (
+	ModPermAll          ModPermission = "all"
+	ModPermAccess       ModPermission = "access"
+	ModPermChatConfig   ModPermission = "chat_config"
+	ModPermChatOperator ModPermission = "chat_operator"
+	ModPermConfig       ModPermission = "config"
+	ModPermFlair        ModPermission = "flair"
+	ModPermMail         ModPermission = "mail"
+	ModPermPosts        ModPermission = "posts"
+	ModPermWiki         ModPermission = "wiki"
+)
@@ -25,6 +25,11 @@ func (ur *UserReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON converts UserReport back into the format used by reddit
+func (ur UserReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{ur.Reason, ur.Count})
+}
+
 // ModReport is a submission report from a mod.
 // Unlike UserReport, this includes the name of the mod
 type ModReport struct {
@@ -46,6 +51,11 @@ func (mr *ModReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON converts ModReport back into the format used by reddit
+func (mr ModReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{mr.Reason, mr.Mod})
+}
+
 // AllReports simply combines ModReports & UserReports
 type AllReports struct {
 	Num  int
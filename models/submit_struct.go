@@ -0,0 +1,26 @@
+package models
+
+// SubmitResult is returned when you create a post.
+// Image & video posts are processed by reddit after submitting, so their ID isn't known yet.
+// Watch WebsocketURL or the users new posts to find them once they're live.
+type SubmitResult struct {
+	// ID is the ID of the post without prefix, i.e. "abc123". Empty for image & video posts.
+	ID string `json:"id"`
+	// Name is the full ID of the post, i.e. "t3_abc123". Empty for image & video posts.
+	Name RedditID `json:"name"`
+	URL  string   `json:"url"`
+	// UserSubmittedPage links to the submitted posts of the user, set for image & video posts.
+	UserSubmittedPage string `json:"user_submitted_page"`
+	// WebsocketURL announces when an image or video post is live.
+	WebsocketURL string `json:"websocket_url"`
+}
+
+// MediaAsset is a file uploaded to reddit, ready to be used in a post.
+type MediaAsset struct {
+	// ID is used to reference the file in galleries.
+	ID string `json:"asset_id"`
+	// URL is used to reference the file in image & video posts.
+	URL string `json:"url"`
+	// WebsocketURL announces when reddit finished processing the file.
+	WebsocketURL string `json:"websocket_url"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Wiki defines a Wiki Page
 type Wiki struct {
 	ContentMD    string        `json:"content_md"`
@@ -9,4 +11,75 @@ type Wiki struct {
 	RevisionBy   RedditElement `json:"revision_by"`
 	RevisionID   string        `json:"revision_id"`
 	ContentHTML  string        `json:"content_html"`
+	// DiffContent shows the changes between two revisions as HTML. It is only set by WikiRevisionDiff.
+	DiffContent string `json:"diffcontent"`
+}
+
+// WikiRevision is an entry in the revision history of a wiki page
+type WikiRevision struct {
+	ID        string  `json:"id"`
+	Page      string  `json:"page"`
+	Reason    string  `json:"reason"`
+	Timestamp float64 `json:"timestamp"`
+	// Author is nil if the account has been deleted.
+	Author *RedditElement `json:"author"`
+	// Hidden revisions are left out of the public history.
+	Hidden bool `json:"revision_hidden"`
+	// Subreddit is set by mira, reddit doesn't send it.
+	Subreddit string `json:"-"`
+}
+
+// GetID returns the ID of the WikiRevision
+func (r WikiRevision) GetID() RedditID { return RedditID(r.ID) }
+
+// CreatedAt returns time.Time the revision was made
+func (r WikiRevision) CreatedAt() time.Time { return time.Unix(int64(r.Timestamp), 0) }
+
+// GetURL returns the link to the page at this revision
+func (r WikiRevision) GetURL() string {
+	return "https://www.reddit.com/r/" + r.Subreddit + "/wiki/" + r.Page + "?v=" + r.ID
 }
+
+// GetAuthor returns the name of the redditor who made the revision, or "" if the account has been deleted
+func (r WikiRevision) GetAuthor() string {
+	if r.Author == nil {
+		return ""
+	}
+	if u, ok := r.Author.Data.(*Redditor); ok {
+		return u.Name
+	}
+	return ""
+}
+
+// WikiSettings defines who may see & edit a wiki page
+type WikiSettings struct {
+	PermLevel WikiPermLevel `json:"permlevel"`
+	// Editors may edit the page regardless of PermLevel.
+	Editors []RedditElement `json:"editors"`
+	// Listed pages are shown in the list of wiki pages.
+	Listed bool `json:"listed"`
+}
+
+// EditorNames returns the names of the Editors
+func (s WikiSettings) EditorNames() []string {
+	ret := make([]string, 0, len(s.Editors))
+	for _, e := range s.Editors {
+		if u, ok := e.Data.(*Redditor); ok {
+			ret = append(ret, u.Name)
+		}
+	}
+	return ret
+}
+
+// WikiPermLevel defines who may edit a wiki page
+type WikiPermLevel int
+
+// List of all WikiPermLevels
+const (
+	// WikiPermSubreddit uses the wiki settings of the subreddit
+	WikiPermSubreddit WikiPermLevel = 0
+	// WikiPermContributors allows approved wiki contributors only
+	WikiPermContributors WikiPermLevel = 1
+	// WikiPermMods allows moderators only
+	WikiPermMods WikiPermLevel = 2
+)
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWikiRevisionAuthor(t *testing.T) {
+	// reddit sends null for revisions of deleted accounts
+	data := `{"kind": "Listing", "data": {"children": [
+		{"id": "b", "page": "index", "reason": "typo", "timestamp": 1700000100, "revision_hidden": false,
+		 "author": {"kind": "t2", "data": {"name": "alice"}}},
+		{"id": "a", "page": "index", "reason": null, "timestamp": 1700000000, "revision_hidden": true,
+		 "author": null}
+	], "after": null}}`
+	var list struct {
+		Data struct {
+			Children []*WikiRevision `json:"children"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		t.Fatal(err)
+	}
+	revs := list.Data.Children
+	if len(revs) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revs))
+	}
+	if revs[0].GetAuthor() != "alice" || revs[0].Hidden {
+		t.Errorf("got revision by %q (hidden: %t), want a visible one by alice", revs[0].GetAuthor(), revs[0].Hidden)
+	}
+	if revs[1].Author != nil || revs[1].GetAuthor() != "" || !revs[1].Hidden {
+		t.Errorf("got revision by %q (hidden: %t), want a hidden one without author", revs[1].GetAuthor(), revs[1].Hidden)
+	}
+	if revs[1].CreatedAt().Unix() != 1700000000 {
+		t.Errorf("got revision created at %s", revs[1].CreatedAt())
+	}
+
+	// authors that aren't redditors are ignored as well
+	rev := WikiRevision{Author: &RedditElement{Kind: KSubreddit, Data: &Subreddit{}}}
+	if rev.GetAuthor() != "" {
+		t.Errorf("got author %q for a subreddit", rev.GetAuthor())
+	}
+}
@@ -0,0 +1,169 @@
+package mira_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestModerators(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.AddRelationship("test", "moderator", &models.Relationship{Name: "alice", ModPermissions: []models.ModPermission{models.ModPermAll}})
+	srv.AddRelationship("test", "moderator", &models.Relationship{Name: "bob", ModPermissions: []models.ModPermission{models.ModPermPosts}})
+
+	mods, err := reddit.Subreddit("test").Moderators()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// newest first
+	if len(mods) != 2 || mods[0].Name != "bob" || mods[1].Name != "alice" {
+		t.Fatalf("got %d moderators, want bob & alice", len(mods))
+	}
+	if !mods[0].HasPermission(models.ModPermPosts) || mods[0].HasPermission(models.ModPermWiki) {
+		t.Errorf("bob has permissions %v, want posts only", mods[0].ModPermissions)
+	}
+	if !mods[1].HasPermission(models.ModPermWiki) {
+		t.Error("alice has all permissions, but not wiki")
+	}
+}
+
+func TestModeratorInvites(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		perms []models.ModPermission
+		// sent are the permissions sent to reddit, want those the moderator ends up with
+		sent string
+		want []models.ModPermission
+	}{
+		{name: "full", sent: "+all", want: []models.ModPermission{models.ModPermAll}},
+		{name: "all", perms: []models.ModPermission{models.ModPermPosts, models.ModPermAll}, sent: "+all",
+			want: []models.ModPermission{models.ModPermAll}},
+		{name: "some", perms: []models.ModPermission{models.ModPermPosts, models.ModPermWiki}, sent: "-all,+posts,+wiki",
+			want: []models.ModPermission{models.ModPermPosts, models.ModPermWiki}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			sub := reddit.Subreddit("test")
+			if err := sub.InviteModerator("miratest", tc.perms...); err != nil {
+				t.Fatal(err)
+			}
+			actions := srv.ActionsTo("/r/test/api/friend")
+			if len(actions) != 1 {
+				t.Fatalf("got %d requests to /r/test/api/friend, want 1", len(actions))
+			}
+			form := actions[0].Form
+			if form.Get("name") != "miratest" || form.Get("type") != "moderator_invite" || form.Get("permissions") != tc.sent {
+				t.Errorf("invited %s to %s with permissions %q, want miratest to moderator_invite with %q",
+					form.Get("name"), form.Get("type"), form.Get("permissions"), tc.sent)
+			}
+
+			if err := sub.AcceptModeratorInvite(); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(srv.ActionsTo("/r/test/api/accept_moderator_invite")); n != 1 {
+				t.Errorf("got %d requests to accept the invite, want 1", n)
+			}
+			if invites := srv.Relationships("test", "moderator_invite"); len(invites) != 0 {
+				t.Errorf("got %d invites left, want none", len(invites))
+			}
+			mods := srv.Relationships("test", "moderator")
+			if len(mods) != 1 || !reflect.DeepEqual(mods[0].ModPermissions, tc.want) {
+				t.Fatalf("got %d moderators, want miratest with %v", len(mods), tc.want)
+			}
+		})
+	}
+}
+
+func TestAcceptModeratorInviteMissing(t *testing.T) {
+	_, reddit := newTestServer(t)
+	if err := reddit.Subreddit("test").AcceptModeratorInvite(); err == nil {
+		t.Error("accepting a missing invite succeeded")
+	}
+}
+
+func TestSetModeratorPermissions(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		invited  bool
+		listType string
+	}{
+		{name: "moderator", listType: "moderator"},
+		{name: "invite", invited: true, listType: "moderator_invite"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			srv.AddRelationship("test", tc.listType, &models.Relationship{Name: "bob", ModPermissions: []models.ModPermission{models.ModPermAll}})
+
+			err := reddit.Subreddit("test").SetModeratorPermissions("bob", tc.invited, models.ModPermFlair, models.ModPermMail)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actions := srv.ActionsTo("/r/test/api/setpermissions")
+			if len(actions) != 1 {
+				t.Fatalf("got %d requests to /r/test/api/setpermissions, want 1", len(actions))
+			}
+			form := actions[0].Form
+			if form.Get("name") != "bob" || form.Get("type") != tc.listType || form.Get("permissions") != "-all,+flair,+mail" {
+				t.Errorf("set permissions of %s in %s to %q, want bob in %s to \"-all,+flair,+mail\"",
+					form.Get("name"), form.Get("type"), form.Get("permissions"), tc.listType)
+			}
+			want := []models.ModPermission{models.ModPermFlair, models.ModPermMail}
+			if rels := srv.Relationships("test", tc.listType); len(rels) != 1 || !reflect.DeepEqual(rels[0].ModPermissions, want) {
+				t.Errorf("got %d entries, want bob with %v", len(rels), want)
+			}
+
+			if err := reddit.Subreddit("test").RemoveModerator("bob", tc.invited); err != nil {
+				t.Fatal(err)
+			}
+			actions = srv.ActionsTo("/r/test/api/unfriend")
+			if len(actions) != 1 || actions[0].Form.Get("name") != "bob" || actions[0].Form.Get("type") != tc.listType {
+				t.Errorf("got unfriend requests %+v, want one for bob in %s", actions, tc.listType)
+			}
+			if rels := srv.Relationships("test", tc.listType); len(rels) != 0 {
+				t.Errorf("bob is still in %s", tc.listType)
+			}
+		})
+	}
+}
+
+func TestLeaveModeration(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.AddRelationship("Test", "moderator", &models.Relationship{Name: "miratest"})
+	if err := reddit.Subreddit("Test").LeaveModeration(); err != nil {
+		t.Fatal(err)
+	}
+	actions := srv.ActionsTo("/api/leavemoderator")
+	if len(actions) != 1 || actions[0].Form.Get("id") != "t5_test" {
+		t.Fatalf("got leavemoderator requests %+v, want one for t5_test", actions)
+	}
+	if mods := srv.Relationships("Test", "moderator"); len(mods) != 0 {
+		t.Errorf("got %d moderators, want none", len(mods))
+	}
+}
+
+func TestContributors(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	sub := reddit.Subreddit("test")
+	if err := sub.AddContributor("alice"); err != nil {
+		t.Fatal(err)
+	}
+	actions := srv.ActionsTo("/r/test/api/friend")
+	if len(actions) != 1 || actions[0].Form.Get("name") != "alice" || actions[0].Form.Get("type") != "contributor" {
+		t.Fatalf("got friend requests %+v, want one for contributor alice", actions)
+	}
+	if rels := srv.Relationships("test", "contributor"); len(rels) != 1 || rels[0].Name != "alice" {
+		t.Fatalf("got %d contributors, want alice", len(rels))
+	}
+
+	if err := sub.RemoveContributor("alice"); err != nil {
+		t.Fatal(err)
+	}
+	actions = srv.ActionsTo("/r/test/api/unfriend")
+	if len(actions) != 1 || actions[0].Form.Get("name") != "alice" || actions[0].Form.Get("type") != "contributor" {
+		t.Fatalf("got unfriend requests %+v, want one for contributor alice", actions)
+	}
+	if rels := srv.Relationships("test", "contributor"); len(rels) != 0 {
+		t.Errorf("got %d contributors, want none", len(rels))
+	}
+}
@@ -0,0 +1,226 @@
+package mira_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// seedModmail adds n conversations with alice to r/test, and returns their IDs most recently updated first.
+func seedModmail(srv *miratest.Server, n int) []string {
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		updated := base.Add(time.Duration(i) * time.Minute)
+		conv := srv.AddModmail(&models.NewModmailConversation{
+			Conversation: models.ModmailConversation{
+				Subject:     "question",
+				Owner:       models.ModmailOwner{DisplayName: "test", Type: "subreddit", ID: "t5_test"},
+				Participant: models.ModmailAuthor{Name: "alice", IsParticipant: true, ID: "t2_alice"},
+				LastUpdated: &updated,
+				IsRepliable: true,
+			},
+			User: models.ModmailUser{Name: "alice", ID: "t2_alice"},
+		})
+		ids[n-1-i] = conv.Conversation.ID
+	}
+	return ids
+}
+
+func TestModMailPaging(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		n    int
+		// after are the indexes of the conversations sent as cursor with each request, -1 for none
+		after []int
+	}{
+		// the last page isn't full, so it is the end
+		{name: "partial last page", n: 5, after: []int{-1, 1, 3}},
+		// the last page is full, so one more page is requested to find the end
+		{name: "full last page", n: 4, after: []int{-1, 1, 3}},
+		{name: "empty", n: 0, after: []int{-1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			ids := seedModmail(srv, tc.n)
+
+			it := reddit.Subreddit("test").IterModMail(models.ModmailAll, mira.IteratorOptions{PageSize: 2})
+			var got []string
+			for it.Next() {
+				got = append(got, it.Item().Conversation.ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(ids, ",") {
+				t.Errorf("got conversations %v, want %v", got, ids)
+			}
+
+			requests := srv.RequestsTo("/api/mod/conversations")
+			if len(requests) != len(tc.after) {
+				t.Fatalf("sent %d requests, want %d", len(requests), len(tc.after))
+			}
+			for i, r := range requests {
+				var want string
+				if tc.after[i] >= 0 {
+					want = ids[tc.after[i]]
+				}
+				if r.Form.Get("after") != want || r.Form.Get("limit") != "2" || r.Form.Get("entity") != "test" {
+					t.Errorf("request %d: after %q, limit %q & entity %q, want after %q, limit 2 & entity test",
+						i, r.Form.Get("after"), r.Form.Get("limit"), r.Form.Get("entity"), want)
+				}
+			}
+		})
+	}
+}
+
+func TestModMail(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	ids := seedModmail(srv, 3)
+
+	convs, err := reddit.Subreddit("test", "other").ModMail(models.ModmailAll, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convs) != 2 || convs[0].Conversation.ID != ids[0] || convs[1].Conversation.ID != ids[1] {
+		t.Errorf("got %d conversations, want the 2 most recent ones", len(convs))
+	}
+	requests := srv.RequestsTo("/api/mod/conversations")
+	if len(requests) != 1 || requests[0].Form.Get("entity") != "test,other" || requests[0].Form.Get("after") != "" {
+		t.Errorf("got requests %+v, want one for test,other", requests)
+	}
+
+	if _, err := reddit.Me().ModMail(models.ModmailAll, 10); err != nil {
+		t.Errorf("listing the modmail of all subreddits failed: %v", err)
+	}
+	if _, err := reddit.Redditor("alice").ModMail(models.ModmailAll, 10); err == nil {
+		t.Error("listing the modmail of a redditor succeeded")
+	}
+}
+
+func TestModMailReply(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	id := seedModmail(srv, 1)[0]
+
+	conv, err := reddit.ReplyModMail(id, "Hi alice", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.Conversation.ID != id {
+		t.Errorf("got conversation %s, want %s", conv.Conversation.ID, id)
+	}
+	conv, err = reddit.AddModMailNote(id, "alice asks a lot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := srv.ActionsTo("/api/mod/conversations/" + id)
+	if len(actions) != 2 {
+		t.Fatalf("got %d requests to the conversation, want 2", len(actions))
+	}
+	for i, want := range []map[string]string{
+		{"body": "Hi alice", "isAuthorHidden": "true", "isInternal": "false"},
+		{"body": "alice asks a lot", "isInternal": "true"},
+	} {
+		for k, v := range want {
+			if got := actions[i].Form.Get(k); got != v {
+				t.Errorf("request %d: %s = %q, want %q", i, k, got, v)
+			}
+		}
+	}
+
+	msgs := conv.SortedMessages()
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if msgs[0].Body != "Hi alice" || !msgs[0].Author.IsHidden || msgs[0].IsInternal {
+		t.Errorf("got reply %+v, want a public one by the subreddit", msgs[0])
+	}
+	if msgs[1].Body != "alice asks a lot" || !msgs[1].IsInternal {
+		t.Errorf("got note %+v, want a private one", msgs[1])
+	}
+}
+
+func TestModMailActions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// before is called first, to undo it with do
+		before func(r *mira.Reddit, id string) error
+		do     func(r *mira.Reddit, id string) error
+		method string
+		path   string
+		form   map[string]string
+		check  func(conv *models.NewModmailConversation) bool
+	}{
+		{name: "archive", do: (*mira.Reddit).ArchiveModMail, method: "POST", path: "archive",
+			check: func(conv *models.NewModmailConversation) bool { return conv.Conversation.IsArchived() }},
+		{name: "unarchive", before: (*mira.Reddit).ArchiveModMail, do: (*mira.Reddit).UnarchiveModMail, method: "POST", path: "unarchive",
+			check: func(conv *models.NewModmailConversation) bool { return !conv.Conversation.IsArchived() }},
+		{name: "highlight", do: (*mira.Reddit).HighlightModMail, method: "POST", path: "highlight",
+			check: func(conv *models.NewModmailConversation) bool { return conv.Conversation.IsHighlighted }},
+		{name: "unhighlight", before: (*mira.Reddit).HighlightModMail, do: (*mira.Reddit).UnhighlightModMail, method: "DELETE", path: "highlight",
+			check: func(conv *models.NewModmailConversation) bool { return !conv.Conversation.IsHighlighted }},
+		{name: "mute", do: func(r *mira.Reddit, id string) error { return r.MuteModMailUser(id, 168) },
+			method: "POST", path: "mute", form: map[string]string{"num_hours": "168"},
+			check: func(conv *models.NewModmailConversation) bool { return conv.User.MuteStatus.IsMuted }},
+		{name: "unmute", before: func(r *mira.Reddit, id string) error { return r.MuteModMailUser(id, 72) },
+			do: (*mira.Reddit).UnmuteModMailUser, method: "POST", path: "unmute",
+			check: func(conv *models.NewModmailConversation) bool { return !conv.User.MuteStatus.IsMuted }},
+		{name: "temp ban", do: func(r *mira.Reddit, id string) error { return r.TempBanModMailUser(id, 3) },
+			method: "POST", path: "temp_ban", form: map[string]string{"duration": "3"},
+			check: func(conv *models.NewModmailConversation) bool { return conv.User.BanStatus.IsBanned }},
+		{name: "unban", before: func(r *mira.Reddit, id string) error { return r.TempBanModMailUser(id, 1) },
+			do: (*mira.Reddit).UnbanModMailUser, method: "POST", path: "unban",
+			check: func(conv *models.NewModmailConversation) bool { return !conv.User.BanStatus.IsBanned }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			id := seedModmail(srv, 1)[0]
+			if tc.before != nil {
+				if err := tc.before(reddit, id); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before := len(srv.Actions())
+			if err := tc.do(reddit, id); err != nil {
+				t.Fatal(err)
+			}
+			actions := srv.Actions()[before:]
+			if len(actions) != 1 || actions[0].Method != tc.method || actions[0].Path != "/api/mod/conversations/"+id+"/"+tc.path {
+				t.Fatalf("got requests %+v, want one %s to %s", actions, tc.method, tc.path)
+			}
+			for k, v := range tc.form {
+				if got := actions[0].Form.Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+			if !tc.check(srv.Modmail(id)) {
+				t.Errorf("conversation %s wasn't changed", id)
+			}
+		})
+	}
+}
+
+func TestModMailActionErrors(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	id := seedModmail(srv, 1)[0]
+	for _, hours := range []int{0, 24, 100} {
+		err := reddit.MuteModMailUser(id, hours)
+		if err == nil || !strings.Contains(err.Error(), "mute duration must be 72, 168 or 672 hours") {
+			t.Errorf("muting for %d hours: got error %v", hours, err)
+		}
+	}
+	for _, days := range []int{0, 1000} {
+		err := reddit.TempBanModMailUser(id, days)
+		if err == nil || !strings.Contains(err.Error(), "ban duration must be between 1 and 999 days") {
+			t.Errorf("banning for %d days: got error %v", days, err)
+		}
+	}
+	if actions := srv.Actions(); len(actions) != 0 {
+		t.Errorf("got %d actions, want none", len(actions))
+	}
+}
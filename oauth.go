@@ -26,7 +26,7 @@ func (t *transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 // newOAuthSession creates a new session for those who want to log into a
 // reddit account via OAuth.
 func newOAuthSession(creds Credentials) *Reddit {
-	r := &Reddit{creds: creds}
+	r := &Reddit{creds: creds, endpoints: creds.Endpoints.withDefaults()}
 
 	if len(r.creds.UserAgent) == 0 {
 		r.creds.UserAgent = "unconfigured reddit bot using https://github.com/ttgmpsn/mira"
@@ -37,8 +37,8 @@ func newOAuthSession(creds Credentials) *Reddit {
 		ClientID:     r.creds.ClientID,
 		ClientSecret: r.creds.ClientSecret,
 		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://www.reddit.com/api/v1/authorize",
-			TokenURL: "https://www.reddit.com/api/v1/access_token",
+			AuthURL:  r.endpoints.AuthURL,
+			TokenURL: r.endpoints.TokenURL,
 		},
 		RedirectURL: r.creds.RedirectURL,
 	}
@@ -54,12 +54,18 @@ func newOAuthSession(creds Credentials) *Reddit {
 // Creds are taken from the data provided to Init.
 // Tokens are refreshed automatically shortly before the session runs out.
 func (c *Reddit) LoginAuth() error {
+	return c.LoginAuthContext(context.Background())
+}
+
+// LoginAuthContext is like LoginAuth, but with a context for the login request.
+// Token refreshes later on are not affected by ctx.
+func (c *Reddit) LoginAuthContext(ctx context.Context) error {
 	if len(c.creds.Username) == 0 || len(c.creds.Password) == 0 {
 		return errors.New("no username or password provided to Init")
 	}
 
 	// Fetch OAuth token.
-	t, err := c.OAuthConfig.PasswordCredentialsToken(c.ctx, c.creds.Username, c.creds.Password)
+	t, err := c.OAuthConfig.PasswordCredentialsToken(c.authContext(ctx), c.creds.Username, c.creds.Password)
 	if err != nil {
 		return err
 	}
@@ -93,7 +99,13 @@ func (c *Reddit) AuthCodeURL(state string, scopes []string) string {
 // You can optionally pass a TokenNotifyFunc to get notified when the token changes (i.e. to
 // store it into a database). Pass nil if you do not want to use this.
 func (c *Reddit) CodeAuth(code string, f TokenNotifyFunc) error {
-	t, err := c.OAuthConfig.Exchange(c.ctx, code)
+	return c.CodeAuthContext(context.Background(), code, f)
+}
+
+// CodeAuthContext is like CodeAuth, but with a context for the token request.
+// Token refreshes later on are not affected by ctx.
+func (c *Reddit) CodeAuthContext(ctx context.Context, code string, f TokenNotifyFunc) error {
+	t, err := c.OAuthConfig.Exchange(c.authContext(ctx), code)
 	if err != nil {
 		return err
 	}
@@ -101,6 +113,12 @@ func (c *Reddit) CodeAuth(code string, f TokenNotifyFunc) error {
 	return c.SetToken(t, c.OAuthConfig.Scopes, f)
 }
 
+// authContext returns ctx with the HTTP client used for authentication requests, which sets
+// the User-Agent.
+func (c *Reddit) authContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, c.ctx.Value(oauth2.HTTPClient))
+}
+
 // SetToken manually assigns a token to the Reddit object.
 // This is useful if you have your token information saved from a prior run.
 // You can optionally pass a TokenNotifyFunc to get notified when the token changes (i.e. to
@@ -127,10 +145,11 @@ func (c *Reddit) SetToken(t *oauth2.Token, scopes []string, f TokenNotifyFunc) e
 	return nil
 }
 
-// SetDefault gets sensible default values for streams.
+// SetDefault gets sensible default values for streams & retries.
 func (c *Reddit) SetDefault() {
 	c.Values = redditVals{
 		GetSubmissionFromCommentTries: 32,
+		Retry:                         DefaultRetryPolicy,
 	}
 }
 
@@ -0,0 +1,38 @@
+package mira_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+)
+
+func TestAuthContext(t *testing.T) {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reddit := mira.Init(srv.Credentials())
+	if err := reddit.LoginAuthContext(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoginAuthContext with cancelled context returned %v", err)
+	}
+	if err := reddit.CodeAuthContext(cancelled, "code", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("CodeAuthContext with cancelled context returned %v", err)
+	}
+
+	if err := reddit.LoginAuthContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reddit.Me().Info(); err != nil {
+		t.Errorf("Me().Info() after LoginAuthContext: %v", err)
+	}
+	if err := reddit.CodeAuthContext(context.Background(), "code", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reddit.Me().Info(); err != nil {
+		t.Errorf("Me().Info() after CodeAuthContext: %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package mira
+
+import (
+	"context"
+	"time"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// StreamOptions control how a stream polls reddit. Set them with Queued.WithStreamOptions
+// before starting the stream. The zero value uses defaults that fit the stream.
+type StreamOptions struct {
+	// Interval is the time between two polls when the stream starts.
+	Interval time.Duration
+	// MinInterval and MaxInterval limit how the interval adapts to activity: it is shortened
+	// while polls return many new items, and extended while they return none. They default to
+	// a fifth and four times Interval. Set both to Interval to poll at a fixed rate.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// PageSize is the number of items requested per poll, up to 100 (the default).
+	PageSize int
+	// Remember is the number of sent items that are remembered to filter out duplicates.
+	// Items are remembered as long as they are in the listing. Defaults to 1000, and is at
+	// least PageSize.
+	Remember int
+	// Buffer is the capacity of the stream channel. Once it is full, polling pauses until
+	// you receive from the stream. Defaults to 100.
+	Buffer int
+	// Checkpoints saves the newest item delivered, so a restarted stream resumes where it
+	// stopped: everything posted in the meantime is delivered first, up to Backfill items.
+	// Items count as delivered once they are in the stream channel, so receive everything
+	// left in it after closing the stream. Mod queue & modmail streams don't support
+	// checkpoints, since they aren't sorted by date.
+	Checkpoints CheckpointStore
+	// CheckpointKey identifies the stream in Checkpoints. It defaults to a key derived from
+	// the stream, i.e. "posts/r/pics". Set it if multiple bots share a store.
+	CheckpointKey string
+	// Backfill is the maximum number of items delivered when resuming. Defaults to 1000.
+	Backfill int
+
+	// clock is only set in tests.
+	clock clock
+}
+
+// withDefaults fills in unset options. interval is the default poll interval of the stream.
+func (opts StreamOptions) withDefaults(interval time.Duration) StreamOptions {
+	if opts.Interval <= 0 {
+		opts.Interval = interval
+	}
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = opts.Interval / 5
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = opts.Interval * 4
+	}
+	if opts.MinInterval > opts.Interval {
+		opts.MinInterval = opts.Interval
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	if opts.PageSize <= 0 || opts.PageSize > 100 {
+		opts.PageSize = 100
+	}
+	if opts.Remember <= 0 {
+		opts.Remember = 1000
+	}
+	if opts.Remember < opts.PageSize {
+		// otherwise items of the same page push each other out
+		opts.Remember = opts.PageSize
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 100
+	}
+	if opts.Backfill <= 0 {
+		opts.Backfill = 1000
+	}
+	if opts.clock == nil {
+		opts.clock = realClock{}
+	}
+	return opts
+}
+
+// WithStreamOptions returns a copy of the queued object that uses opts for all streams started from it.
+func (q Queued) WithStreamOptions(opts StreamOptions) Queued {
+	q.streamOpts = opts
+	return q
+}
+
+// poller polls a listing & sends every item once.
+type poller[T models.RedditThing] struct {
+	opts     StreamOptions
+	interval time.Duration
+	seen     *seenSet
+	key      func(T) string
+	// fetch returns the newest items first.
+	fetch func(ctx context.Context) ([]T, error)
+	// delivered is called after new items were sent, if set.
+	delivered func(ctx context.Context, items []T)
+	// checkpointKey is set if checkpoints are saved.
+	checkpointKey string
+	// pending are backfilled items sent with the first poll, newest first.
+	pending []T
+	// resumed is the Checkpoint the stream resumed from. Items up to it are skipped in the first poll.
+	resumed *Checkpoint
+}
+
+// newPoller creates a poller. opts must have been passed through withDefaults.
+func newPoller[T models.RedditThing](opts StreamOptions, key func(T) string, fetch func(ctx context.Context) ([]T, error)) *poller[T] {
+	return &poller[T]{
+		opts:     opts,
+		interval: opts.Interval,
+		seen:     newSeenSet(opts.Remember),
+		key:      key,
+		fetch:    fetch,
+	}
+}
+
+// markSent remembers an item as sent without sending it.
+func (p *poller[T]) markSent(item T) {
+	p.seen.add(p.key(item))
+}
+
+// resume enables checkpoints if set in the options, using key unless another CheckpointKey is set.
+// If a Checkpoint has been saved before, backfill is called to get everything newer than it,
+// and true is returned.
+func (p *poller[T]) resume(ctx context.Context, key string, backfill func(ctx context.Context, cp Checkpoint) ([]T, error)) (bool, error) {
+	if p.opts.Checkpoints == nil {
+		return false, nil
+	}
+	p.checkpointKey = key
+	if p.opts.CheckpointKey != "" {
+		p.checkpointKey = p.opts.CheckpointKey
+	}
+	cp, err := p.opts.Checkpoints.Load(p.checkpointKey)
+	if err != nil || cp.Last == "" {
+		return false, err
+	}
+	p.resumed = &cp
+	p.pending, err = backfill(ctx, cp)
+	return true, err
+}
+
+// saveCheckpoint saves item as the newest item delivered, if checkpoints are enabled.
+func (p *poller[T]) saveCheckpoint(item T, errC chan error) {
+	if p.checkpointKey == "" {
+		return
+	}
+	cp := Checkpoint{Last: item.GetID(), Created: item.CreatedAt()}
+	if err := p.opts.Checkpoints.Save(p.checkpointKey, cp); err != nil {
+		sendError(errC, err)
+	}
+}
+
+// start polls in a new goroutine and returns the channels of the stream.
+func (p *poller[T]) start(ctx context.Context) (<-chan T, <-chan error, chan struct{}) {
+	sendC := make(chan T, p.opts.Buffer)
+	errC := make(chan error, 10)
+	stop := make(chan struct{})
+	go p.run(ctx, stop, sendC, errC)
+	return sendC, errC, stop
+}
+
+// run calls fetch & sends everything it hasn't sent before to sendC, oldest first, until stop is
+// closed, ctx is cancelled or fetch fails permanently. Errors are sent to errC. Both channels are
+// closed once run returns.
+func (p *poller[T]) run(ctx context.Context, stop <-chan struct{}, sendC chan<- T, errC chan error) {
+	defer close(errC)
+	defer close(sendC)
+	next := p.opts.clock.After(0)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-next:
+		}
+
+		items, err := p.fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			sendError(errC, err)
+			// transient errors don't end the stream, we just try again next round
+			if !isTransient(err) {
+				return
+			}
+		}
+		// backfilled items are older than the first page, they are sent first
+		items, p.pending = append(items, p.pending...), nil
+		var fresh []T
+		for i := len(items) - 1; i >= 0; i-- {
+			k := p.key(items[i])
+			if p.seen.has(k) {
+				// items are remembered as long as they are listed, i.e. if they stay in the mod
+				// queue while many others come & go
+				p.seen.add(k)
+				continue
+			}
+			if p.resumed != nil && (items[i].GetID() == p.resumed.Last || items[i].CreatedAt().Before(p.resumed.Created)) {
+				// delivered before the restart
+				p.seen.add(k)
+				continue
+			}
+			select {
+			case sendC <- items[i]:
+			case <-stop:
+				p.finishRound(ctx, fresh, errC)
+				return
+			case <-ctx.Done():
+				p.finishRound(ctx, fresh, errC)
+				return
+			}
+			p.seen.add(k)
+			fresh = append(fresh, items[i])
+		}
+		p.finishRound(ctx, fresh, errC)
+		if err == nil {
+			p.resumed = nil
+			p.adapt(len(fresh))
+		}
+		next = p.opts.clock.After(p.interval)
+	}
+}
+
+// finishRound handles the items sent in one round of polling.
+func (p *poller[T]) finishRound(ctx context.Context, fresh []T, errC chan error) {
+	if len(fresh) == 0 {
+		return
+	}
+	if p.delivered != nil {
+		p.delivered(ctx, fresh)
+	}
+	p.saveCheckpoint(fresh[len(fresh)-1], errC)
+}
+
+// adapt shortens the interval while polls return many new items, so nothing is missed on busy
+// listings, and extends it while they return none, to save requests on quiet ones.
+func (p *poller[T]) adapt(fresh int) {
+	switch {
+	case fresh == 0:
+		p.interval = p.interval * 3 / 2
+	case fresh*2 >= p.opts.PageSize:
+		p.interval /= 2
+	}
+	if p.interval < p.opts.MinInterval {
+		p.interval = p.opts.MinInterval
+	}
+	if p.interval > p.opts.MaxInterval {
+		p.interval = p.opts.MaxInterval
+	}
+}
+
+// sendError sends err to errC without blocking. If errC is full, the oldest error is dropped.
+func sendError(errC chan error, err error) {
+	select {
+	case errC <- err:
+		return
+	default:
+	}
+	select {
+	case <-errC:
+	default:
+	}
+	select {
+	case errC <- err:
+	default:
+	}
+}
+
+// seenSet remembers the last size keys added to it.
+type seenSet struct {
+	keys  map[string]int // position of the newest entry of a key in order
+	order []string       // ring buffer, next is the oldest key
+	next  int
+}
+
+func newSeenSet(size int) *seenSet {
+	return &seenSet{
+		keys:  make(map[string]int, size),
+		order: make([]string, size),
+	}
+}
+
+func (s *seenSet) has(key string) bool {
+	_, ok := s.keys[key]
+	return ok
+}
+
+// add adds key, or makes it the newest key if it is already in the set.
+func (s *seenSet) add(key string) {
+	// the oldest entry is overwritten, unless its key has been added again since
+	if old := s.order[s.next]; old != "" && s.keys[old] == s.next {
+		delete(s.keys, old)
+	}
+	s.order[s.next] = key
+	s.keys[key] = s.next
+	s.next = (s.next + 1) % len(s.order)
+}
@@ -0,0 +1,168 @@
+package mira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestSeenSet(t *testing.T) {
+	s := newSeenSet(3)
+	for _, k := range []string{"a", "b", "c"} {
+		s.add(k)
+	}
+	s.add("a") // a is the newest key now
+	s.add("d") // evicts b
+	for k, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if s.has(k) != want {
+			t.Errorf("has(%s) = %v, want %v", k, !want, want)
+		}
+	}
+	s.add("e") // evicts c
+	s.add("f") // evicts a
+	if s.has("a") || s.has("c") || !s.has("e") || !s.has("f") {
+		t.Errorf("set contains %v, want d, e & f", s.keys)
+	}
+	if len(s.keys) > 3 {
+		t.Errorf("set contains %d keys, want at most 3", len(s.keys))
+	}
+}
+
+func TestPollerAdapt(t *testing.T) {
+	opts := StreamOptions{Interval: 10 * time.Second, PageSize: 100}.withDefaults(0)
+	p := newPoller[*models.Post](opts, nil, nil)
+	var got []time.Duration
+	for i := 0; i < 6; i++ {
+		p.adapt(0)
+		got = append(got, p.interval)
+	}
+	want := fmt.Sprint([]time.Duration{15 * time.Second, 22500 * time.Millisecond, 33750 * time.Millisecond, 40 * time.Second, 40 * time.Second, 40 * time.Second})
+	if fmt.Sprint(got) != want {
+		t.Errorf("empty rounds: intervals %v, want %s", got, want)
+	}
+
+	got = nil
+	for i := 0; i < 6; i++ {
+		p.adapt(60)
+		got = append(got, p.interval)
+	}
+	want = fmt.Sprint([]time.Duration{20 * time.Second, 10 * time.Second, 5 * time.Second, 2500 * time.Millisecond, 2 * time.Second, 2 * time.Second})
+	if fmt.Sprint(got) != want {
+		t.Errorf("busy rounds: intervals %v, want %s", got, want)
+	}
+
+	// a few new items keep the interval
+	p.adapt(10)
+	if p.interval != 2*time.Second {
+		t.Errorf("interval changed to %s with few new items", p.interval)
+	}
+}
+
+func TestPollerInterval(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts StreamOptions
+		// fresh is the number of new items each poll returns
+		fresh int
+		want  []time.Duration
+	}{
+		{name: "quiet", opts: StreamOptions{Interval: 20 * time.Second, MaxInterval: 80 * time.Second},
+			want: []time.Duration{30 * time.Second, 45 * time.Second, 67500 * time.Millisecond, 80 * time.Second, 80 * time.Second}},
+		{name: "busy", opts: StreamOptions{Interval: 160 * time.Second, MinInterval: 10 * time.Second, PageSize: 2}, fresh: 2,
+			want: []time.Duration{80 * time.Second, 40 * time.Second, 20 * time.Second, 10 * time.Second, 10 * time.Second}},
+		{name: "steady", opts: StreamOptions{Interval: 30 * time.Second, PageSize: 10}, fresh: 1,
+			want: []time.Duration{30 * time.Second, 30 * time.Second, 30 * time.Second}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clk := newFakeClock()
+			tc.opts.clock = clk
+			n := 0
+			p := newPoller(tc.opts.withDefaults(0), func(p *models.Post) string { return p.ID }, func(ctx context.Context) ([]*models.Post, error) {
+				var posts []*models.Post
+				for i := 0; i < tc.fresh; i++ {
+					n++
+					posts = append(posts, &models.Post{ID: fmt.Sprint(n)})
+				}
+				return posts, nil
+			})
+			c, errs, stop := p.start(context.Background())
+			defer func() {
+				close(stop)
+				for range c {
+				}
+				for err := range errs {
+					t.Error(err)
+				}
+			}()
+
+			// the first poll is right away, each further one waits for the adapted interval
+			if d := <-clk.waits; d != 0 {
+				t.Fatalf("first poll after %s, want 0", d)
+			}
+			var got []time.Duration
+			for range tc.want {
+				d := <-clk.waits
+				got = append(got, d)
+				clk.Advance(d)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("intervals %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSendError(t *testing.T) {
+	errC := make(chan error, 3)
+	for i := 1; i <= 5; i++ {
+		sendError(errC, fmt.Errorf("error %d", i))
+	}
+	close(errC)
+	var got []string
+	for err := range errC {
+		got = append(got, err.Error())
+	}
+	if want := "[error 3 error 4 error 5]"; fmt.Sprint(got) != want {
+		t.Errorf("got %v, want the newest errors %s", got, want)
+	}
+}
+
+func TestStreamOptionsDefaults(t *testing.T) {
+	opts := StreamOptions{PageSize: 50, Remember: 10, Interval: time.Second, MinInterval: 2 * time.Second}.withDefaults(time.Minute)
+	if opts.Remember != 50 {
+		t.Errorf("Remember = %d, want at least PageSize", opts.Remember)
+	}
+	if opts.MinInterval != time.Second || opts.MaxInterval != 4*time.Second {
+		t.Errorf("intervals %s - %s, want 1s - 4s", opts.MinInterval, opts.MaxInterval)
+	}
+	if opts.Buffer != 100 || opts.Backfill != 1000 {
+		t.Errorf("Buffer = %d & Backfill = %d, want defaults", opts.Buffer, opts.Backfill)
+	}
+}
+
+func TestPollerErrors(t *testing.T) {
+	permanent := errors.New("permanent")
+	calls := 0
+	p := newPoller(StreamOptions{Interval: time.Millisecond}.withDefaults(0), func(p *models.Post) string { return p.ID }, func(ctx context.Context) ([]*models.Post, error) {
+		calls++
+		if calls == 1 {
+			return nil, &ServerError{APIError: APIError{StatusCode: 503}}
+		}
+		return nil, permanent
+	})
+	c, errs, _ := p.start(context.Background())
+	var got []error
+	for err := range errs {
+		got = append(got, err)
+	}
+	if _, ok := <-c; ok {
+		t.Error("C is still open")
+	}
+	if len(got) != 2 || !errors.Is(got[1], permanent) || calls != 2 {
+		t.Errorf("got errors %v after %d polls, want a transient & the permanent one after 2", got, calls)
+	}
+}
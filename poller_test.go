@@ -0,0 +1,157 @@
+package mira_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Items are sent once, even if the stream is told to remember fewer than a page.
+func TestStreamNoDuplicates(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	opts := fastStream
+	opts.PageSize = 5
+	opts.Remember = 2
+	// redditor streams request a full page on every poll
+	stream, err := reddit.Redditor("alice").WithStreamOptions(opts).StreamPosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := float64(time.Now().Add(-time.Hour).Unix())
+	var want []models.RedditID
+	for round := 0; round < 8; round++ {
+		for i := 0; i < 3; i++ {
+			created++
+			want = append(want, srv.AddPost(&models.Post{Subreddit: "test", Author: "alice", CreatedUTC: created}).Name)
+		}
+		for i, post := range receive(t, stream.C, 3) {
+			if post.GetID() != want[round*3+i] {
+				t.Fatalf("got %s, want %s", post.GetID(), want[round*3+i])
+			}
+		}
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
+
+// Items that stay in a listing aren't forgotten while others come & go.
+func TestStreamRemembersListedItems(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	opts := fastStream
+	opts.PageSize = 10
+	opts.Remember = 10
+	stuck := srv.AddPost(&models.Post{Subreddit: "test", Title: "nobody handles this"})
+	srv.Report(stuck.Name, "spam")
+
+	stream, err := reddit.Subreddit("test").WithStreamOptions(opts).StreamModQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(t, stream.C, 1)
+	for round := 0; round < 5; round++ {
+		var batch []*models.Post
+		for i := 0; i < 5; i++ {
+			p := srv.AddPost(&models.Post{Subreddit: "test"})
+			srv.Report(p.Name, "spam")
+			batch = append(batch, p)
+		}
+		for _, sub := range receive(t, stream.C, 5) {
+			if sub.GetID() == stuck.Name {
+				t.Fatalf("%s has been sent again", stuck.Name)
+			}
+		}
+		for _, p := range batch {
+			if err := reddit.Post(string(p.Name)).Approve(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
+
+func TestStreamClose(t *testing.T) {
+	for _, viaContext := range []bool{false, true} {
+		srv, reddit := newTestServer(t)
+		for i := 0; i < 5; i++ {
+			srv.AddPost(&models.Post{Subreddit: "test"})
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		opts := fastStream
+		opts.Buffer = 1
+		stream, err := reddit.Subreddit("test").WithStreamOptions(opts).StreamPostsContext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// nobody receives, so the stream is stuck sending the second post
+		time.Sleep(50 * time.Millisecond)
+		if viaContext {
+			cancel()
+		} else {
+			close(stream.Close)
+		}
+
+		timeout := time.After(time.Second)
+		for c, errs := stream.C, stream.Errors; c != nil || errs != nil; {
+			select {
+			case _, ok := <-c:
+				if !ok {
+					c = nil
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+				} else {
+					t.Error(err)
+				}
+			case <-timeout:
+				t.Fatalf("stream didn't stop (via context: %v)", viaContext)
+			}
+		}
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	const path = "/r/test/new.json"
+	srv, reddit := newTestServer(t)
+	reddit.Values.Retry.MaxAttempts = 1
+	stream, err := reddit.Subreddit("test").WithStreamOptions(fastStream).StreamPosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// temporary errors are reported, but the stream goes on
+	srv.Fail(path, 1, http.StatusServiceUnavailable, "")
+	err = receive(t, stream.Errors, 1)[0]
+	var srvErr *mira.ServerError
+	if !errors.As(err, &srvErr) {
+		t.Errorf("got error %v, want ServerError", err)
+	}
+	post := srv.AddPost(&models.Post{Subreddit: "test"})
+	if got := receive(t, stream.C, 1); got[0].GetID() != post.Name {
+		t.Errorf("got %s, want %s", got[0].GetID(), post.Name)
+	}
+
+	// other errors end it
+	srv.Fail(path, 1, http.StatusForbidden, "")
+	err = receive(t, stream.Errors, 1)[0]
+	var fErr *mira.ForbiddenError
+	if !errors.As(err, &fErr) {
+		t.Errorf("got error %v, want ForbiddenError", err)
+	}
+	select {
+	case _, ok := <-stream.C:
+		if ok {
+			t.Error("got an item after the stream ended")
+		}
+	case <-time.After(time.Second):
+		t.Error("C hasn't been closed")
+	}
+}
@@ -0,0 +1,142 @@
+package mira
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the request budget reddit reported for the token of a Reddit instance.
+// Reddit sends the values on every OAuth response, see https://github.com/reddit-archive/reddit/wiki/API#rules
+type RateLimit struct {
+	// Remaining is the number of requests left in the current period.
+	Remaining float64
+	// Used is the number of requests used in the current period.
+	Used int
+	// Reset is the time the current period ends and the budget is refilled.
+	Reset time.Time
+}
+
+// rateLimiter keeps track of the X-Ratelimit headers returned by reddit and
+// delays requests once the budget for the current period is used up.
+// The zero value is ready to use and is safe for concurrent use.
+type rateLimiter struct {
+	mu    sync.Mutex // guards limit, known & probe
+	limit RateLimit
+	known bool
+	// clock is only set in tests, nil uses the real clock.
+	clock clock
+	// probe is set while the first request after the end of a period is on its way. The new
+	// budget is unknown until its response arrives, so other requests wait for probe to be closed.
+	probe chan struct{}
+}
+
+// reserve takes one request from the current budget. If the budget is used up, it returns
+// how long the caller has to wait until the period resets, or a channel to wait on while
+// another request finds out the new budget. isProbe is true if the caller was chosen to
+// find out the new budget, it has to call endProbe once the response arrived.
+func (l *rateLimiter) reserve() (wait time.Duration, probe <-chan struct{}, isProbe bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.known {
+		return 0, nil, false
+	}
+	if l.probe != nil {
+		return 0, l.probe, false
+	}
+	if now := l.getClock().Now(); now.Before(l.limit.Reset) {
+		if l.limit.Remaining >= 1 {
+			// Count the request right away, so concurrent callers can't
+			// use the same slot before reddit tells us the new values.
+			l.limit.Remaining--
+			l.limit.Used++
+			return 0, nil, false
+		}
+		return l.limit.Reset.Sub(now), nil, false
+	}
+	// The period has ended. Instead of letting everyone who waited for it through at
+	// once, only one request is sent until reddit tells us the new budget.
+	l.probe = make(chan struct{})
+	return 0, nil, true
+}
+
+// wait blocks until a request may be sent or ctx is done.
+// If isProbe is true, endProbe must be called once the response arrived.
+func (l *rateLimiter) wait(ctx context.Context) (isProbe bool, err error) {
+	for {
+		d, probe, isProbe := l.reserve()
+		switch {
+		case probe != nil:
+			select {
+			case <-probe:
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		case d > 0:
+			if err := sleepClock(ctx, l.getClock(), d); err != nil {
+				return false, err
+			}
+		default:
+			return isProbe, nil
+		}
+	}
+}
+
+// endProbe lets the requests waiting for the new budget continue. It is called after update,
+// or after the request failed without a response.
+func (l *rateLimiter) endProbe() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.probe != nil {
+		close(l.probe)
+		l.probe = nil
+	}
+}
+
+// update stores the budget from the headers of a reddit response.
+// Responses without rate limit headers (i.e. from the token endpoint) are ignored.
+func (l *rateLimiter) update(h http.Header) {
+	remaining, err := strconv.ParseFloat(h.Get("X-Ratelimit-Remaining"), 64)
+	if err != nil {
+		return
+	}
+	used, _ := strconv.Atoi(h.Get("X-Ratelimit-Used"))
+	reset, _ := strconv.ParseFloat(h.Get("X-Ratelimit-Reset"), 64)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = RateLimit{
+		Remaining: remaining,
+		Used:      used,
+		Reset:     l.getClock().Now().Add(time.Duration(reset * float64(time.Second))),
+	}
+	l.known = true
+}
+
+// getClock returns the clock used to wait for resets.
+func (l *rateLimiter) getClock() clock {
+	if l.clock == nil {
+		return realClock{}
+	}
+	return l.clock
+}
+
+// get returns a copy of the current budget.
+func (l *rateLimiter) get() (RateLimit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit, l.known
+}
+
+// RateLimit returns the current request budget of the token used by this Reddit instance,
+// as reported by the last response from reddit. The boolean is false if no response with
+// rate limit information has been received yet.
+//
+// All requests made through MiraRequest are counted against this budget. Once it is used up,
+// requests block until reddit resets it, so multiple goroutines can safely share one instance.
+// After the reset, a single request is sent to learn the new budget before the others continue.
+func (c *Reddit) RateLimit() (RateLimit, bool) {
+	return c.limiter.get()
+}
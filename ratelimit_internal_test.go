@@ -0,0 +1,96 @@
+package mira
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// rateLimitHeader returns the headers reddit sends with remaining requests left for reset seconds.
+func rateLimitHeader(remaining, used, reset int) http.Header {
+	h := http.Header{}
+	h.Set("X-Ratelimit-Remaining", strconv.Itoa(remaining))
+	h.Set("X-Ratelimit-Used", strconv.Itoa(used))
+	h.Set("X-Ratelimit-Reset", strconv.Itoa(reset))
+	return h
+}
+
+func TestRateLimiterReset(t *testing.T) {
+	clk := newFakeClock()
+	l := &rateLimiter{clock: clk}
+	if d, probe, isProbe := l.reserve(); d != 0 || probe != nil || isProbe {
+		t.Fatalf("the budget is unknown, but reserve() = %s, %v, %v", d, probe, isProbe)
+	}
+
+	l.update(rateLimitHeader(1, 99, 10))
+	if d, _, _ := l.reserve(); d != 0 {
+		t.Fatalf("waiting %s with 1 request left", d)
+	}
+	if d, _, _ := l.reserve(); d != 10*time.Second {
+		t.Fatalf("waiting %s with the budget used up, want until the reset in 10s", d)
+	}
+
+	type result struct {
+		isProbe bool
+		err     error
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			isProbe, err := l.wait(context.Background())
+			results <- result{isProbe, err}
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if d := <-clk.waits; d != 10*time.Second {
+			t.Fatalf("waiting %s, want 10s", d)
+		}
+	}
+	clk.Advance(10 * time.Second)
+
+	// only one request is let through to find out the new budget
+	if r := <-results; !r.isProbe || r.err != nil {
+		t.Fatalf("wait() = %v, %v; want the probe", r.isProbe, r.err)
+	}
+	select {
+	case r := <-results:
+		t.Fatalf("wait() = %v, %v before the new budget is known", r.isProbe, r.err)
+	default:
+	}
+	// the probe used up the new budget, so the other request waits for the next period
+	l.update(rateLimitHeader(0, 1, 10))
+	l.endProbe()
+	if d := <-clk.waits; d != 10*time.Second {
+		t.Fatalf("waiting %s, want 10s", d)
+	}
+	select {
+	case r := <-results:
+		t.Fatalf("wait() = %v, %v before the next reset", r.isProbe, r.err)
+	default:
+	}
+	clk.Advance(10 * time.Second)
+	if r := <-results; !r.isProbe || r.err != nil {
+		t.Fatalf("wait() = %v, %v; want the next probe", r.isProbe, r.err)
+	}
+	l.endProbe()
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	clk := newFakeClock()
+	l := &rateLimiter{clock: clk}
+	l.update(rateLimitHeader(0, 100, 10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error)
+	go func() {
+		_, err := l.wait(ctx)
+		errC <- err
+	}()
+	<-clk.waits
+	cancel()
+	if err := <-errC; err != context.Canceled {
+		t.Errorf("wait() = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,44 @@
+package mira_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira/miratest"
+)
+
+func TestRateLimit(t *testing.T) {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a 429 would be retried, make it fail the test instead
+	reddit.Values.Retry.MaxAttempts = 1
+	srv.SetRateLimit(1, time.Second)
+
+	start := time.Now()
+	if _, err := reddit.Subreddit("test").Posts("new", "all", 1); err != nil {
+		t.Fatal(err)
+	}
+	limit, ok := reddit.RateLimit()
+	if !ok || limit.Remaining != 0 || limit.Used != 1 {
+		t.Fatalf("RateLimit() = %+v, %v; want 0 remaining & 1 used", limit, ok)
+	}
+	if d := time.Until(limit.Reset); d <= 0 || d > time.Second {
+		t.Errorf("RateLimit().Reset is %s from now, want (0, 1s]", d)
+	}
+
+	// The call waits for the reset instead of failing with 429. How requests waiting together
+	// share the new budget is tested with a fake clock in TestRateLimiterReset.
+	if _, err := reddit.Subreddit("test").Posts("new", "all", 1); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 900*time.Millisecond {
+		t.Errorf("call finished after %s, want it to wait for the reset", d)
+	}
+	if limit, _ := reddit.RateLimit(); limit.Remaining != 0 || limit.Used != 1 {
+		t.Errorf("RateLimit() = %+v, want 0 remaining & 1 used", limit)
+	}
+}
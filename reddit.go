@@ -2,6 +2,7 @@ package mira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,40 +15,102 @@ import (
 
 // MiraRequest can be used to make custom requests to the reddit API.
 func (c *Reddit) MiraRequest(method string, target string, payload map[string]string) ([]byte, error) {
+	return c.MiraRequestContext(context.Background(), method, target, payload)
+}
+
+// MiraRequestContext is like MiraRequest, but with a context.
+// Failed requests are retried according to Values.Retry.
+func (c *Reddit) MiraRequestContext(ctx context.Context, method string, target string, payload map[string]string) ([]byte, error) {
 	values := url.Values{}
 	for i, v := range payload {
 		values.Set(i, v)
 	}
 
+	return c.withRetries(ctx, method, func() ([]byte, error) {
+		return c.doRequest(ctx, method, target, values)
+	})
+}
+
+// miraRequestJSON is like MiraRequestContext, but sends payload as a JSON body.
+// Some newer endpoints (i.e. galleries & polls) only accept JSON.
+func (c *Reddit) miraRequestJSON(ctx context.Context, method string, target string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.withRetries(ctx, method, func() ([]byte, error) {
+		r, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Content-Type", "application/json")
+		return c.send(ctx, r)
+	})
+}
+
+// withRetries calls do until it succeeds, fails permanently or the attempts of Values.Retry are used up.
+func (c *Reddit) withRetries(ctx context.Context, method string, do func() ([]byte, error)) ([]byte, error) {
+	attempts := 1
+	if method == "GET" || c.Values.Retry.RetryPOST {
+		attempts = c.Values.Retry.MaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		data, err := do()
+		if err == nil || attempt >= attempts || !isTransient(err) {
+			return data, err
+		}
+		if err := sleepContext(ctx, c.Values.Retry.backoff(attempt, err)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Reddit) doRequest(ctx context.Context, method string, target string, values url.Values) ([]byte, error) {
 	var r *http.Request
 	var err error
 	if method == "GET" {
 		values := fmt.Sprintf("?%s", values.Encode())
-		r, err = http.NewRequest(method, target+values, nil)
+		r, err = http.NewRequestWithContext(ctx, method, target+values, nil)
 	} else {
-		r, err = http.NewRequest(method, target, strings.NewReader(values.Encode()))
+		r, err = http.NewRequestWithContext(ctx, method, target, strings.NewReader(values.Encode()))
 		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		r.Header.Add("Content-Length", strconv.Itoa(len(values.Encode())))
 	}
 	if err != nil {
 		return nil, err
 	}
+	return c.send(ctx, r)
+}
+
+// send sends r once the rate limit allows it, and returns the response body or the error reddit returned.
+func (c *Reddit) send(ctx context.Context, r *http.Request) ([]byte, error) {
+	isProbe, err := c.limiter.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isProbe {
+		// runs after limiter.update, or if the request fails
+		defer c.limiter.endProbe()
+	}
 	response, err := c.Client.Do(r)
 	if err != nil {
 		return nil, err
 	}
 	defer response.Body.Close()
+	c.limiter.update(response.Header)
 	buf := new(bytes.Buffer)
-	buf.ReadFrom(response.Body)
+	if _, err := buf.ReadFrom(response.Body); err != nil {
+		return nil, err
+	}
 	data := buf.Bytes()
-	if err := findRedditError(data); err != nil {
+	if err := findRedditError(response, data); err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (c *Reddit) miraRequestListing(method string, target string, payload map[string]string) (*models.Listing, error) {
-	ans, err := c.MiraRequest(method, target, payload)
+func (c *Reddit) miraRequestListing(ctx context.Context, method string, target string, payload map[string]string) (*models.Listing, error) {
+	ans, err := c.MiraRequestContext(ctx, method, target, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -63,130 +126,150 @@ func (c *Reddit) miraRequestListing(method string, target string, payload map[st
 }
 
 // Me queues up the next action to be about the logged in user.
-func (c *Reddit) Me() *Reddit {
-	return c.addQueue("", "me")
+func (c *Reddit) Me() Queued {
+	return c.queue("me", "me")
 }
 
 // Subreddit queues up the next action to be about one or multuple Subreddits.
-func (c *Reddit) Subreddit(name ...string) *Reddit {
-	return c.addQueue(strings.Join(name, "+"), models.KSubreddit)
+func (c *Reddit) Subreddit(name ...string) Queued {
+	return c.queue(strings.Join(name, "+"), models.KSubreddit)
 }
 
 // Post queues up the next action to be about a certain Post.
-func (c *Reddit) Post(name string) *Reddit {
-	return c.addQueue(name, models.KPost)
+func (c *Reddit) Post(name string) Queued {
+	return c.queue(name, models.KPost)
 }
 
 // Comment queues up the next action to be about a certain comment.
-func (c *Reddit) Comment(name string) *Reddit {
-	return c.addQueue(name, models.KComment)
+func (c *Reddit) Comment(name string) Queued {
+	return c.queue(name, models.KComment)
+}
+
+// Message queues up the next action to be about a certain message in your inbox.
+// Comment replies & mentions are queued with their comment ID (t1_XXXXX).
+func (c *Reddit) Message(name string) Queued {
+	return c.queue(name, models.KMessage)
 }
 
 // Redditor queues up the next action to be about a certain Redditor.
-func (c *Reddit) Redditor(name string) *Reddit {
-	return c.addQueue(name, models.KRedditor)
+// Multiple Redditors can only be queued up for streams.
+func (c *Reddit) Redditor(name ...string) Queued {
+	return c.queue(strings.Join(name, "+"), models.KRedditor)
 }
 
-// Posts gets posts for the last queued object.
+// Posts gets posts for the queued object.
 // Valid objects: Subreddit, Redditor
-func (c *Reddit) Posts(sort string, tdur string, limit int) ([]*models.Post, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+func (q Queued) Posts(sort string, tdur string, limit int) ([]*models.Post, error) {
+	return q.PostsContext(context.Background(), sort, tdur, limit)
+}
+
+// PostsContext is like Posts, but with a context.
+func (q Queued) PostsContext(ctx context.Context, sort string, tdur string, limit int) ([]*models.Post, error) {
+	switch q.kind {
 	case models.KSubreddit:
-		return c.getSubredditPosts(name, sort, tdur, limit)
+		return q.getSubredditPosts(ctx, q.name, sort, tdur, limit)
 	case models.KRedditor:
-		return c.getRedditorPosts(name, sort, tdur, limit)
+		return q.getRedditorPosts(ctx, q.name, sort, tdur, limit)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option for posts", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option for posts", q.kind)
 	}
 }
 
-// PostsAfter gets posts for the last queued object after a given item.
+// PostsAfter gets posts for the queued object after a given item.
 // Valid objects: Subreddit, Redditor
-func (c *Reddit) PostsAfter(last models.RedditID, limit int) ([]*models.Post, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+func (q Queued) PostsAfter(last models.RedditID, limit int) ([]*models.Post, error) {
+	return q.PostsAfterContext(context.Background(), last, limit)
+}
+
+// PostsAfterContext is like PostsAfter, but with a context.
+func (q Queued) PostsAfterContext(ctx context.Context, last models.RedditID, limit int) ([]*models.Post, error) {
+	switch q.kind {
 	case models.KSubreddit:
-		return c.getSubredditPostsAfter(name, last, limit)
+		return q.getSubredditPostsAfter(ctx, q.name, last, limit)
 	case models.KRedditor:
-		return c.getRedditorPostsAfter(name, last, limit)
+		return q.getRedditorPostsAfter(ctx, q.name, last, limit)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option for postsafter", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option for postsafter", q.kind)
 	}
 }
 
-// Comments gets comments for the last queued object.
+// Comments gets comments for the queued object.
 // Valid objects: Subreddit, Post, Redditor
-func (c *Reddit) Comments(sort string, tdur string, limit int) ([]*models.Comment, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+func (q Queued) Comments(sort string, tdur string, limit int) ([]*models.Comment, error) {
+	return q.CommentsContext(context.Background(), sort, tdur, limit)
+}
+
+// CommentsContext is like Comments, but with a context.
+func (q Queued) CommentsContext(ctx context.Context, sort string, tdur string, limit int) ([]*models.Comment, error) {
+	switch q.kind {
 	case models.KSubreddit:
-		return c.getSubredditComments(name, sort, tdur, limit)
+		return q.getSubredditComments(ctx, q.name, sort, tdur, limit)
 	case models.KPost:
-		comments, err := c.getPostComments(models.RedditID(name), sort, tdur, limit)
+		comments, err := q.getPostComments(ctx, models.RedditID(q.name), sort, tdur, limit)
 		if err != nil {
 			return nil, err
 		}
 		return comments, nil
 	case models.KRedditor:
-		return c.getRedditorComments(name, sort, tdur, limit)
+		return q.getRedditorComments(ctx, q.name, sort, tdur, limit)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option for comments", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option for comments", q.kind)
 	}
 }
 
 // Info returns general information about the queued object as a mira.Interface.
-func (c *Reddit) Info() (models.RedditThing, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+func (q Queued) Info() (models.RedditThing, error) {
+	return q.InfoContext(context.Background())
+}
+
+// InfoContext is like Info, but with a context.
+func (q Queued) InfoContext(ctx context.Context) (models.RedditThing, error) {
+	switch q.kind {
 	case "me":
-		return c.getMe()
+		return q.getMe(ctx)
 	case models.KPost:
-		return c.getPost(models.RedditID(name))
+		return q.getPost(ctx, models.RedditID(q.name))
 	case models.KComment:
-		return c.getComment(models.RedditID(name))
+		return q.getComment(ctx, models.RedditID(q.name))
 	case models.KSubreddit:
-		return c.getSubreddit(name)
+		return q.getSubreddit(ctx, q.name)
 	case models.KRedditor:
-		return c.getUser(name)
+		return q.getUser(ctx, q.name)
 	default:
 		return nil, fmt.Errorf("returning type is not defined")
 	}
 }
 
-// CommentsAfter gets comments for the last queued object after a given item.
+// CommentsAfter gets comments for the queued object after a given item.
 // Valid objects: Subreddit, Redditor
-func (c *Reddit) CommentsAfter(sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+func (q Queued) CommentsAfter(sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
+	return q.CommentsAfterContext(context.Background(), sort, last, limit)
+}
+
+// CommentsAfterContext is like CommentsAfter, but with a context.
+func (q Queued) CommentsAfterContext(ctx context.Context, sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
+	switch q.kind {
 	case models.KSubreddit:
-		return c.getSubredditCommentsAfter(name, sort, last, limit)
+		return q.getSubredditCommentsAfter(ctx, q.name, sort, last, limit)
 	case models.KRedditor:
-		return c.getRedditorCommentsAfter(name, sort, last, limit)
+		return q.getRedditorCommentsAfter(ctx, q.name, sort, last, limit)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option for comments", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option for comments", q.kind)
 	}
 }
 
-func (c *Reddit) checkType(rtype ...models.RedditKind) (string, models.RedditKind, error) {
-	name, ttype := c.getQueue()
-	if name == "" {
+func (q Queued) checkType(rtype ...models.RedditKind) (string, models.RedditKind, error) {
+	if q.name == "" {
 		return "", "", fmt.Errorf("identifier is empty")
 	}
-	if !findElem(ttype, rtype) {
+	if !findElem(q.kind, rtype) {
 		return "", "", fmt.Errorf("the passed type is not a valid type for this call | expected: %s", rtype)
 	}
-	return name, ttype, nil
+	return q.name, q.kind, nil
 }
 
-func (c *Reddit) addQueue(name string, ttype models.RedditKind) *Reddit {
-	c.Chain <- &chainVals{Name: name, Type: ttype}
-	return c
-}
-
-func (c *Reddit) getQueue() (string, models.RedditKind) {
-	next := <-c.Chain
-	return next.Name, next.Type
+func (c *Reddit) queue(name string, ttype models.RedditKind) Queued {
+	return Queued{Reddit: c, name: name, kind: ttype}
 }
 
 func findElem(elem models.RedditKind, arr []models.RedditKind) bool {
@@ -198,41 +281,34 @@ func findElem(elem models.RedditKind, arr []models.RedditKind) bool {
 	return false
 }
 
-// Submissions gets submissions for the last queued object.
+// Submissions gets submissions for the queued object.
 // Valid objects: Redditor
-func (c *Reddit) Submissions(limit int) ([]models.Submission, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
-	case models.KRedditor:
-		return c.getRedditorSubmissions(name, limit)
-	default:
-		return nil, fmt.Errorf("'%s' type does not have an option for submissions", ttype)
-	}
+func (q Queued) Submissions(limit int) ([]models.Submission, error) {
+	return q.SubmissionsContext(context.Background(), limit)
 }
 
-// SubmissionsAfter gets submissions for the last queued object after a given item.
-// Valid objects: Redditor
-func (c *Reddit) SubmissionsAfter(last models.RedditID, limit int) ([]models.Submission, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+// SubmissionsContext is like Submissions, but with a context.
+func (q Queued) SubmissionsContext(ctx context.Context, limit int) ([]models.Submission, error) {
+	switch q.kind {
 	case models.KRedditor:
-		return c.getRedditorSubmissionsAfter(name, last, limit)
+		return q.getRedditorSubmissions(ctx, q.name, limit)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option for submissionsafter", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option for submissions", q.kind)
 	}
 }
 
-// RedditErr is an error returned from the Reddit API.
-type RedditErr struct {
-	Message string `json:"message"`
-	Error   string `json:"error"`
+// SubmissionsAfter gets submissions for the queued object after a given item.
+// Valid objects: Redditor
+func (q Queued) SubmissionsAfter(last models.RedditID, limit int) ([]models.Submission, error) {
+	return q.SubmissionsAfterContext(context.Background(), last, limit)
 }
 
-func findRedditError(data []byte) error {
-	object := &RedditErr{}
-	json.Unmarshal(data, object)
-	if object.Message != "" || object.Error != "" {
-		return fmt.Errorf("%s | error code: %s", object.Message, object.Error)
+// SubmissionsAfterContext is like SubmissionsAfter, but with a context.
+func (q Queued) SubmissionsAfterContext(ctx context.Context, last models.RedditID, limit int) ([]models.Submission, error) {
+	switch q.kind {
+	case models.KRedditor:
+		return q.getRedditorSubmissionsAfter(ctx, q.name, last, limit)
+	default:
+		return nil, fmt.Errorf("'%s' type does not have an option for submissionsafter", q.kind)
 	}
-	return nil
 }
@@ -0,0 +1,127 @@
+package mira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+	"golang.org/x/oauth2"
+)
+
+// MediaUpload is a file to upload for an image, video or gallery post.
+type MediaUpload struct {
+	// Name is the file name, i.e. "cat.png".
+	Name string
+	// MimeType of the file, i.e. "image/png". If empty, it is guessed from the extension of Name.
+	MimeType string
+	// Data is read once while uploading.
+	Data io.Reader
+	// Caption & OutboundURL are shown with the item in galleries, and ignored otherwise.
+	Caption     string
+	OutboundURL string
+}
+
+// UploadMedia uploads a file to reddit. The returned asset can be used in posts.
+// SubmitWithOptions uploads files itself, so this is only needed to reuse files.
+func (c *Reddit) UploadMedia(file MediaUpload) (*models.MediaAsset, error) {
+	return c.UploadMediaContext(context.Background(), file)
+}
+
+// UploadMediaContext is like UploadMedia, but with a context.
+func (c *Reddit) UploadMediaContext(ctx context.Context, file MediaUpload) (*models.MediaAsset, error) {
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(file.Name))
+	}
+	if mimeType == "" {
+		return nil, fmt.Errorf("couldn't guess mime type of '%s'", file.Name)
+	}
+	if file.Data == nil {
+		return nil, fmt.Errorf("no data to upload for '%s'", file.Name)
+	}
+
+	// reddit hands out a lease to upload the file to its storage
+	target := c.endpoints.OAuth + "/api/media/asset.json"
+	ans, err := c.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"filepath": file.Name,
+		"mimetype": mimeType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	lease := struct {
+		Args struct {
+			Action string `json:"action"`
+			Fields []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"args"`
+		Asset models.MediaAsset `json:"asset"`
+	}{}
+	if err := json.Unmarshal(ans, &lease); err != nil {
+		return nil, err
+	}
+	action := lease.Args.Action
+	if strings.HasPrefix(action, "//") {
+		action = "https:" + action
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	var key string
+	for _, f := range lease.Args.Fields {
+		if f.Name == "key" {
+			key = f.Value
+		}
+		if err := w.WriteField(f.Name, f.Value); err != nil {
+			return nil, err
+		}
+	}
+	part, err := w.CreateFormFile("file", file.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, file.Data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, "POST", action, body)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	// the storage isn't part of the API, so the OAuth token is not sent along
+	client, ok := c.ctx.Value(oauth2.HTTPClient).(*http.Client)
+	if !ok {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	// the storage answers with a short XML document, which is only of interest if the upload failed
+	msg, err := io.ReadAll(io.LimitReader(response.Body, 4096))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 300 {
+		return nil, fmt.Errorf("uploading '%s' failed with status %s: %s", file.Name, response.Status, bytes.TrimSpace(msg))
+	}
+
+	asset := lease.Asset
+	asset.URL = action + "/" + key
+	return &asset, nil
+}
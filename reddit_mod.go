@@ -1,38 +1,51 @@
 package mira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ttgmpsn/mira/models"
 )
 
-// Approve the last queued object.
+// Approve the queued object.
 // Valid objects: Comment, Post
-func (c *Reddit) Approve() error {
-	name, _, err := c.checkType(models.KComment, models.KPost)
+func (q Queued) Approve() error {
+	return q.ApproveContext(context.Background())
+}
+
+// ApproveContext is like Approve, but with a context.
+func (q Queued) ApproveContext(ctx context.Context) error {
+	name, _, err := q.checkType(models.KComment, models.KPost)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/approve"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/approve"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"id":       name,
 		"api_type": "json",
 	})
 	return err
 }
 
-// Remove mod-removes the last queued object. To remove own comments,
+// Remove mod-removes the queued object. To remove own comments,
 // please use Delete()
 // Valid objects: Comment, Post
-func (c *Reddit) Remove(spam bool) error {
-	name, _, err := c.checkType(models.KComment, models.KPost)
+func (q Queued) Remove(spam bool) error {
+	return q.RemoveContext(context.Background(), spam)
+}
+
+// RemoveContext is like Remove, but with a context.
+func (q Queued) RemoveContext(ctx context.Context, spam bool) error {
+	name, _, err := q.checkType(models.KComment, models.KPost)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/remove"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/remove"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"id":       name,
 		"spam":     strconv.FormatBool(spam),
 		"api_type": "json",
@@ -40,15 +53,20 @@ func (c *Reddit) Remove(spam bool) error {
 	return err
 }
 
-// Distinguish the last queued object.
+// Distinguish the queued object.
 // Valid objects: Comment
-func (c *Reddit) Distinguish(how string, sticky bool) error {
-	name, _, err := c.checkType(models.KComment)
+func (q Queued) Distinguish(how string, sticky bool) error {
+	return q.DistinguishContext(context.Background(), how, sticky)
+}
+
+// DistinguishContext is like Distinguish, but with a context.
+func (q Queued) DistinguishContext(ctx context.Context, how string, sticky bool) error {
+	name, _, err := q.checkType(models.KComment)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/distinguish"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/distinguish"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"id":       name,
 		"how":      how,
 		"sticky":   strconv.FormatBool(sticky),
@@ -57,15 +75,20 @@ func (c *Reddit) Distinguish(how string, sticky bool) error {
 	return err
 }
 
-// UpdateSidebar of the last queued object.
+// UpdateSidebar of the queued object.
 // Valid objects: Subreddit
-func (c *Reddit) UpdateSidebar(text string) error {
-	name, _, err := c.checkType(models.KSubreddit)
+func (q Queued) UpdateSidebar(text string) error {
+	return q.UpdateSidebarContext(context.Background(), text)
+}
+
+// UpdateSidebarContext is like UpdateSidebar, but with a context.
+func (q Queued) UpdateSidebarContext(ctx context.Context, text string) error {
+	name, _, err := q.checkType(models.KSubreddit)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/site_admin"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/site_admin"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"sr":          name,
 		"name":        "None",
 		"description": text,
@@ -78,16 +101,25 @@ func (c *Reddit) UpdateSidebar(text string) error {
 	return err
 }
 
-// ModQueue returns the mod queue from the last queued object.
+// ModQueue returns the mod queue from the queued object.
 // Valid objects: Subreddit
-func (c *Reddit) ModQueue(limit int) ([]models.Submission, error) {
-	sr, ttype := c.getQueue()
-	if ttype != models.KSubreddit {
-		return nil, fmt.Errorf("'%s' type does not have an option for modqueue", ttype)
+func (q Queued) ModQueue(limit int) ([]models.Submission, error) {
+	return q.ModQueueContext(context.Background(), limit)
+}
+
+// ModQueueContext is like ModQueue, but with a context.
+func (q Queued) ModQueueContext(ctx context.Context, limit int) ([]models.Submission, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for modqueue", q.kind)
 	}
 
-	target := RedditOauth + "/r/" + sr + "/about/modqueue.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+	return q.getModListing(ctx, q.name, "modqueue", limit)
+}
+
+// getModListing returns one of the moderation listings of a subreddit: modqueue, reports, spam, edited or unmoderated.
+func (c *Reddit) getModListing(ctx context.Context, sr string, where string, limit int) ([]models.Submission, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/about/" + where + ".json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit": strconv.Itoa(limit),
 	})
 	if err != nil {
@@ -104,19 +136,30 @@ func (c *Reddit) ModQueue(limit int) ([]models.Submission, error) {
 	return ret, nil
 }
 
-// ModLog returns the mod log from the last queued object.
+// ModLog returns the mod log from the queued object.
 // Valid objects: Subreddit
-func (c *Reddit) ModLog(limit int, mod string) ([]*models.ModAction, error) {
-	sr, ttype := c.getQueue()
-	if ttype != models.KSubreddit {
-		return nil, fmt.Errorf("'%s' type does not have an option for modlog", ttype)
+func (q Queued) ModLog(limit int, mod string) ([]*models.ModAction, error) {
+	return q.ModLogContext(context.Background(), limit, mod)
+}
+
+// ModLogContext is like ModLog, but with a context.
+func (q Queued) ModLogContext(ctx context.Context, limit int, mod string) ([]*models.ModAction, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for modlog", q.kind)
 	}
 
-	target := RedditOauth + "/r/" + sr + "/about/log.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+	params := map[string]string{
 		"limit": strconv.Itoa(limit),
-		"mod":   mod,
-	})
+	}
+	if mod != "" {
+		params["mod"] = mod
+	}
+	return q.getModLog(ctx, q.name, params)
+}
+
+func (c *Reddit) getModLog(ctx context.Context, sr string, params map[string]string) ([]*models.ModAction, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/about/log.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, params)
 	if err != nil {
 		return nil, err
 	}
@@ -131,42 +174,359 @@ func (c *Reddit) ModLog(limit int, mod string) ([]*models.ModAction, error) {
 	return ret, nil
 }
 
-// Ban bans a redditor from last queued object.
+// Ban bans a redditor from the queued object. reason is used as the ban reason & the mod note,
+// use BanWithOptions to set them separately.
 // Valid objects: Subreddit
-func (c *Reddit) Ban(redditor string, days int, context, message, reason string) error {
-	subreddit, _, err := c.checkType(models.KSubreddit)
+func (q Queued) Ban(redditor string, days int, banContext, message, reason string) error {
+	return q.BanContext(context.Background(), redditor, days, banContext, message, reason)
+}
+
+// BanContext is like Ban, but with a context.
+func (q Queued) BanContext(ctx context.Context, redditor string, days int, banContext, message, reason string) error {
+	return q.BanWithOptionsContext(ctx, redditor, BanOptions{
+		Duration: days,
+		Reason:   reason,
+		Note:     reason,
+		Message:  message,
+		Context:  models.RedditID(banContext),
+	})
+}
+
+// BanOptions describe a ban.
+type BanOptions struct {
+	// Duration is the length of the ban in days, 1 to 999. 0 bans permanently.
+	Duration int
+	// Reason is the rule that was broken, up to 100 characters. It is shown to moderators only.
+	Reason string
+	// Note is a note for moderators, up to 300 characters.
+	Note string
+	// Message is sent to the banned redditor.
+	Message string
+	// Context is the post or comment the ban is about, if any.
+	Context models.RedditID
+}
+
+// BanWithOptions bans a redditor from the queued object. Banning a redditor that is already
+// banned updates the ban.
+// If reddit rejects the ban, a *ValidationError is returned.
+// Valid objects: Subreddit
+func (q Queued) BanWithOptions(redditor string, opts BanOptions) error {
+	return q.BanWithOptionsContext(context.Background(), redditor, opts)
+}
+
+// BanWithOptionsContext is like BanWithOptions, but with a context.
+func (q Queued) BanWithOptionsContext(ctx context.Context, redditor string, opts BanOptions) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
 	if err != nil {
 		return err
 	}
+	if opts.Duration < 0 || opts.Duration > 999 {
+		return fmt.Errorf("ban duration must be 0 (permanent) to 999 days, got %d", opts.Duration)
+	}
 	args := map[string]string{
 		"name":        redditor,
-		"ban_context": context,
-		"ban_message": message,
-		"ban_reason":  reason,
-		"note":        reason,
+		"ban_context": string(opts.Context),
+		"ban_message": opts.Message,
+		"ban_reason":  opts.Reason,
+		"note":        opts.Note,
+	}
+	if opts.Duration != 0 {
+		args["duration"] = strconv.Itoa(opts.Duration)
+	}
+	return q.friend(ctx, subreddit, "banned", args)
+}
+
+// Unban lifts the ban of a redditor from the queued object.
+// Valid objects: Subreddit
+func (q Queued) Unban(redditor string) error {
+	return q.UnbanContext(context.Background(), redditor)
+}
+
+// UnbanContext is like Unban, but with a context.
+func (q Queued) UnbanContext(ctx context.Context, redditor string) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return q.unfriend(ctx, subreddit, "banned", redditor)
+}
+
+// Mute mutes a redditor in the modmail of the queued object. note is shown to moderators.
+// Valid objects: Subreddit
+func (q Queued) Mute(redditor string, note string) error {
+	return q.MuteContext(context.Background(), redditor, note)
+}
+
+// MuteContext is like Mute, but with a context.
+func (q Queued) MuteContext(ctx context.Context, redditor string, note string) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return q.friend(ctx, subreddit, "muted", map[string]string{
+		"name": redditor,
+		"note": note,
+	})
+}
+
+// Unmute unmutes a redditor in the modmail of the queued object.
+// Valid objects: Subreddit
+func (q Queued) Unmute(redditor string) error {
+	return q.UnmuteContext(context.Background(), redditor)
+}
+
+// UnmuteContext is like Unmute, but with a context.
+func (q Queued) UnmuteContext(ctx context.Context, redditor string) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return q.unfriend(ctx, subreddit, "muted", redditor)
+}
+
+// BanInfo returns the ban of a redditor from the queued object, or nil if the redditor isn't banned.
+// Valid objects: Subreddit
+func (q Queued) BanInfo(redditor string) (*models.Relationship, error) {
+	return q.BanInfoContext(context.Background(), redditor)
+}
+
+// BanInfoContext is like BanInfo, but with a context.
+func (q Queued) BanInfoContext(ctx context.Context, redditor string) (*models.Relationship, error) {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := q.endpoints.OAuth + "/r/" + subreddit + "/about/banned"
+	list, err := q.getUserList(ctx, target, map[string]string{"user": redditor})
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range list.Children {
+		if strings.EqualFold(rel.Name, redditor) {
+			return rel, nil
+		}
+	}
+	return nil, nil
+}
+
+// Moderators returns the moderators of the queued object with their permissions.
+// Valid objects: Subreddit
+func (q Queued) Moderators() ([]*models.Relationship, error) {
+	return q.ModeratorsContext(context.Background())
+}
+
+// ModeratorsContext is like Moderators, but with a context.
+func (q Queued) ModeratorsContext(ctx context.Context) ([]*models.Relationship, error) {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*models.Relationship
+	it := newUserListIterator(ctx, q.Reddit, q.endpoints.OAuth+"/r/"+subreddit+"/about/moderators", nil, IteratorOptions{})
+	for it.Next() {
+		ret = append(ret, it.Item())
+	}
+	return ret, it.Err()
+}
+
+// InviteModerator invites a redditor to moderate the queued object. Without permissions,
+// the redditor gets full permissions.
+// If reddit rejects the invite, a *ValidationError is returned.
+// Valid objects: Subreddit
+func (q Queued) InviteModerator(redditor string, permissions ...models.ModPermission) error {
+	return q.InviteModeratorContext(context.Background(), redditor, permissions...)
+}
+
+// InviteModeratorContext is like InviteModerator, but with a context.
+func (q Queued) InviteModeratorContext(ctx context.Context, redditor string, permissions ...models.ModPermission) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return q.friend(ctx, subreddit, "moderator_invite", map[string]string{
+		"name":        redditor,
+		"permissions": formatPermissions(permissions),
+	})
+}
+
+// SetModeratorPermissions changes the permissions of a moderator of the queued object.
+// Set invited to change the permissions of a pending invite instead. Without permissions,
+// the moderator gets full permissions.
+// Valid objects: Subreddit
+func (q Queued) SetModeratorPermissions(redditor string, invited bool, permissions ...models.ModPermission) error {
+	return q.SetModeratorPermissionsContext(context.Background(), redditor, invited, permissions...)
+}
+
+// SetModeratorPermissionsContext is like SetModeratorPermissions, but with a context.
+func (q Queued) SetModeratorPermissionsContext(ctx context.Context, redditor string, invited bool, permissions ...models.ModPermission) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	listType := "moderator"
+	if invited {
+		listType = "moderator_invite"
+	}
+	target := q.endpoints.OAuth + "/r/" + subreddit + "/api/setpermissions"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"name":        redditor,
+		"type":        listType,
+		"permissions": formatPermissions(permissions),
 		"api_type":    "json",
-		"type":        "banned",
+	})
+	return err
+}
+
+// RemoveModerator removes a moderator from the queued object.
+// Set invited to withdraw a pending invite instead.
+// Valid objects: Subreddit
+func (q Queued) RemoveModerator(redditor string, invited bool) error {
+	return q.RemoveModeratorContext(context.Background(), redditor, invited)
+}
+
+// RemoveModeratorContext is like RemoveModerator, but with a context.
+func (q Queued) RemoveModeratorContext(ctx context.Context, redditor string, invited bool) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	if invited {
+		return q.unfriend(ctx, subreddit, "moderator_invite", redditor)
+	}
+	return q.unfriend(ctx, subreddit, "moderator", redditor)
+}
+
+// AcceptModeratorInvite accepts the invite of the logged in user to moderate the queued object.
+// If there is no invite, a *ValidationError is returned.
+// Valid objects: Subreddit
+func (q Queued) AcceptModeratorInvite() error {
+	return q.AcceptModeratorInviteContext(context.Background())
+}
+
+// AcceptModeratorInviteContext is like AcceptModeratorInvite, but with a context.
+func (q Queued) AcceptModeratorInviteContext(ctx context.Context) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	target := q.endpoints.OAuth + "/r/" + subreddit + "/api/accept_moderator_invite"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"api_type": "json",
+	})
+	return err
+}
+
+// LeaveModeration removes the logged in user from the moderators of the queued object.
+// Valid objects: Subreddit
+func (q Queued) LeaveModeration() error {
+	return q.LeaveModerationContext(context.Background())
+}
+
+// LeaveModerationContext is like LeaveModeration, but with a context.
+func (q Queued) LeaveModerationContext(ctx context.Context) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
 	}
-	if days != 0 {
-		args["duration"] = strconv.Itoa(days)
+	// reddit needs the full ID of the subreddit
+	sr, err := q.getSubreddit(ctx, subreddit)
+	if err != nil {
+		return err
 	}
-	target := RedditOauth + "/r/" + subreddit + "/api/friend"
-	_, err = c.MiraRequest("POST", target, args)
+	target := q.endpoints.OAuth + "/api/leavemoderator"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"id": string(sr.Name),
+	})
 	return err
 }
 
-// GetModMailByID returns the ModMail Conversation for a given modmail ID
-func (c *Reddit) GetModMailByID(conversationID string, markRead bool) (*models.NewModmailConversation, error) {
-	target := RedditOauth + "/api/mod/conversations/" + conversationID
-	ans, err := c.MiraRequest("GET", target, map[string]string{
-		"markRead": strconv.FormatBool(markRead),
+// AddContributor adds a redditor to the approved users of the queued object.
+// Valid objects: Subreddit
+func (q Queued) AddContributor(redditor string) error {
+	return q.AddContributorContext(context.Background(), redditor)
+}
+
+// AddContributorContext is like AddContributor, but with a context.
+func (q Queued) AddContributorContext(ctx context.Context, redditor string) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return q.friend(ctx, subreddit, "contributor", map[string]string{
+		"name": redditor,
 	})
+}
+
+// RemoveContributor removes a redditor from the approved users of the queued object.
+// Valid objects: Subreddit
+func (q Queued) RemoveContributor(redditor string) error {
+	return q.RemoveContributorContext(context.Background(), redditor)
+}
+
+// RemoveContributorContext is like RemoveContributor, but with a context.
+func (q Queued) RemoveContributorContext(ctx context.Context, redditor string) error {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return err
+	}
+	return q.unfriend(ctx, subreddit, "contributor", redditor)
+}
+
+// formatPermissions formats moderator permissions the way reddit expects them, i.e. "-all,+posts,+wiki".
+func formatPermissions(permissions []models.ModPermission) string {
+	if len(permissions) == 0 {
+		return "+all"
+	}
+	ret := []string{"-all"}
+	for _, p := range permissions {
+		if p == models.ModPermAll {
+			return "+all"
+		}
+		ret = append(ret, "+"+string(p))
+	}
+	return strings.Join(ret, ",")
+}
+
+// friend adds a redditor to a user list of a subreddit, i.e. "banned" or "contributor".
+func (c *Reddit) friend(ctx context.Context, sr string, listType string, args map[string]string) error {
+	args["type"] = listType
+	args["api_type"] = "json"
+	target := c.endpoints.OAuth + "/r/" + sr + "/api/friend"
+	_, err := c.MiraRequestContext(ctx, "POST", target, args)
+	return err
+}
+
+// unfriend removes a redditor from a user list of a subreddit.
+func (c *Reddit) unfriend(ctx context.Context, sr string, listType string, redditor string) error {
+	target := c.endpoints.OAuth + "/r/" + sr + "/api/unfriend"
+	_, err := c.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"name":     redditor,
+		"type":     listType,
+		"api_type": "json",
+	})
+	return err
+}
+
+// getUserList requests a page of a user list of a subreddit, i.e. /r/{sr}/about/banned.
+func (c *Reddit) getUserList(ctx context.Context, target string, params map[string]string) (*models.UserList, error) {
+	ans, err := c.MiraRequestContext(ctx, "GET", target, params)
 	if err != nil {
 		return nil, err
 	}
-	ret := &models.NewModmailConversation{}
-	if err := json.Unmarshal([]byte(ans), ret); err != nil {
+	ret := &models.Response{}
+	if err := json.Unmarshal(ans, ret); err != nil {
 		return nil, err
 	}
-	return ret, nil
+	list, ok := ret.Data.(*models.UserList)
+	if !ok {
+		return nil, fmt.Errorf("couldn't convert to UserList struct. Data has Kind '%s'", ret.Kind)
+	}
+	// reddit only sends the whole days left, so the end of the ban is estimated from now
+	now := time.Now()
+	for _, rel := range list.Children {
+		if rel.DaysLeft != nil {
+			rel.Expires = now.AddDate(0, 0, *rel.DaysLeft)
+		}
+	}
+	return list, nil
 }
@@ -0,0 +1,312 @@
+package mira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// GetModMailByID returns the ModMail Conversation for a given modmail ID
+func (c *Reddit) GetModMailByID(conversationID string, markRead bool) (*models.NewModmailConversation, error) {
+	return c.GetModMailByIDContext(context.Background(), conversationID, markRead)
+}
+
+// GetModMailByIDContext is like GetModMailByID, but with a context.
+func (c *Reddit) GetModMailByIDContext(ctx context.Context, conversationID string, markRead bool) (*models.NewModmailConversation, error) {
+	target := c.endpoints.OAuth + "/api/mod/conversations/" + conversationID
+	ans, err := c.MiraRequestContext(ctx, "GET", target, map[string]string{
+		"markRead": strconv.FormatBool(markRead),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.NewModmailConversation{}
+	if err := json.Unmarshal([]byte(ans), ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ModMail returns up to 100 modmail conversations of the queued object matching filter,
+// most recently updated first. Only the last message of each conversation is included,
+// use GetModMailByID for all of them.
+// Valid objects: Subreddit (multiple subreddits are allowed), Me (all subreddits you moderate)
+func (q Queued) ModMail(filter models.ModmailFilter, limit int) ([]*models.NewModmailConversation, error) {
+	return q.ModMailContext(context.Background(), filter, limit)
+}
+
+// ModMailContext is like ModMail, but with a context.
+func (q Queued) ModMailContext(ctx context.Context, filter models.ModmailFilter, limit int) ([]*models.NewModmailConversation, error) {
+	params, err := q.modMailParams(filter)
+	if err != nil {
+		return nil, err
+	}
+	params["limit"] = strconv.Itoa(limit)
+	ret, _, err := q.getModMail(ctx, q.endpoints.OAuth+"/api/mod/conversations", params)
+	return ret, err
+}
+
+// modMailParams returns the parameters to list the modmail of the queued object.
+func (q Queued) modMailParams(filter models.ModmailFilter) (map[string]string, error) {
+	params := map[string]string{"state": string(filter), "sort": "recent"}
+	switch q.kind {
+	case models.KSubreddit:
+		params["entity"] = strings.ReplaceAll(q.name, "+", ",")
+	case "me":
+	default:
+		return nil, fmt.Errorf("'%s' type does not have an option for modmail", q.kind)
+	}
+	return params, nil
+}
+
+// getModMail requests a page of modmail conversations & returns the cursor of the next page ("" at the end).
+func (c *Reddit) getModMail(ctx context.Context, target string, params map[string]string) ([]*models.NewModmailConversation, string, error) {
+	ans, err := c.MiraRequestContext(ctx, "GET", target, params)
+	if err != nil {
+		return nil, "", err
+	}
+	var list struct {
+		Conversations   map[string]models.ModmailConversation `json:"conversations"`
+		ConversationIDs []string                              `json:"conversationIds"`
+		Messages        map[string]models.ModmailMessage      `json:"messages"`
+	}
+	if err := json.Unmarshal(ans, &list); err != nil {
+		return nil, "", err
+	}
+	ret := make([]*models.NewModmailConversation, 0, len(list.ConversationIDs))
+	for _, id := range list.ConversationIDs {
+		conv := &models.NewModmailConversation{
+			Conversation: list.Conversations[id],
+			Messages:     make(map[string]models.ModmailMessage),
+		}
+		for _, obj := range conv.Conversation.ObjIDs {
+			if msg, ok := list.Messages[obj.ID]; ok && obj.Key == "messages" {
+				conv.Messages[obj.ID] = msg
+			}
+		}
+		ret = append(ret, conv)
+	}
+	var after string
+	if limit, _ := strconv.Atoi(params["limit"]); len(ret) > 0 && len(ret) >= limit {
+		after = list.ConversationIDs[len(list.ConversationIDs)-1]
+	}
+	return ret, after, nil
+}
+
+// CreateModMail starts a new modmail conversation with a redditor, or a discussion
+// between the moderators of the queued object if to is empty. Set hideAuthor to send
+// the first message as the subreddit.
+// Valid objects: Subreddit
+func (q Queued) CreateModMail(to, subject, body string, hideAuthor bool) (*models.NewModmailConversation, error) {
+	return q.CreateModMailContext(context.Background(), to, subject, body, hideAuthor)
+}
+
+// CreateModMailContext is like CreateModMail, but with a context.
+func (q Queued) CreateModMailContext(ctx context.Context, to, subject, body string, hideAuthor bool) (*models.NewModmailConversation, error) {
+	subreddit, _, err := q.checkType(models.KSubreddit)
+	if err != nil {
+		return nil, err
+	}
+	target := q.endpoints.OAuth + "/api/mod/conversations"
+	return q.modMailConversation(ctx, "POST", target, map[string]string{
+		"srName":         subreddit,
+		"to":             to,
+		"subject":        subject,
+		"body":           body,
+		"isAuthorHidden": strconv.FormatBool(hideAuthor),
+	})
+}
+
+// ReplyModMail replies to a modmail conversation. Set hideAuthor to reply as the subreddit.
+// The updated conversation is returned.
+func (c *Reddit) ReplyModMail(conversationID, body string, hideAuthor bool) (*models.NewModmailConversation, error) {
+	return c.ReplyModMailContext(context.Background(), conversationID, body, hideAuthor)
+}
+
+// ReplyModMailContext is like ReplyModMail, but with a context.
+func (c *Reddit) ReplyModMailContext(ctx context.Context, conversationID, body string, hideAuthor bool) (*models.NewModmailConversation, error) {
+	target := c.endpoints.OAuth + "/api/mod/conversations/" + conversationID
+	return c.modMailConversation(ctx, "POST", target, map[string]string{
+		"body":           body,
+		"isAuthorHidden": strconv.FormatBool(hideAuthor),
+		"isInternal":     "false",
+	})
+}
+
+// AddModMailNote adds a private note to a modmail conversation, which only moderators can see.
+// The updated conversation is returned.
+func (c *Reddit) AddModMailNote(conversationID, body string) (*models.NewModmailConversation, error) {
+	return c.AddModMailNoteContext(context.Background(), conversationID, body)
+}
+
+// AddModMailNoteContext is like AddModMailNote, but with a context.
+func (c *Reddit) AddModMailNoteContext(ctx context.Context, conversationID, body string) (*models.NewModmailConversation, error) {
+	target := c.endpoints.OAuth + "/api/mod/conversations/" + conversationID
+	return c.modMailConversation(ctx, "POST", target, map[string]string{
+		"body":       body,
+		"isInternal": "true",
+	})
+}
+
+// ArchiveModMail archives a modmail conversation.
+func (c *Reddit) ArchiveModMail(conversationID string) error {
+	return c.ArchiveModMailContext(context.Background(), conversationID)
+}
+
+// ArchiveModMailContext is like ArchiveModMail, but with a context.
+func (c *Reddit) ArchiveModMailContext(ctx context.Context, conversationID string) error {
+	return c.modMailAction(ctx, "POST", conversationID, "archive", nil)
+}
+
+// UnarchiveModMail moves an archived modmail conversation back to the inbox.
+func (c *Reddit) UnarchiveModMail(conversationID string) error {
+	return c.UnarchiveModMailContext(context.Background(), conversationID)
+}
+
+// UnarchiveModMailContext is like UnarchiveModMail, but with a context.
+func (c *Reddit) UnarchiveModMailContext(ctx context.Context, conversationID string) error {
+	return c.modMailAction(ctx, "POST", conversationID, "unarchive", nil)
+}
+
+// HighlightModMail highlights a modmail conversation.
+func (c *Reddit) HighlightModMail(conversationID string) error {
+	return c.HighlightModMailContext(context.Background(), conversationID)
+}
+
+// HighlightModMailContext is like HighlightModMail, but with a context.
+func (c *Reddit) HighlightModMailContext(ctx context.Context, conversationID string) error {
+	return c.modMailAction(ctx, "POST", conversationID, "highlight", nil)
+}
+
+// UnhighlightModMail removes the highlight from a modmail conversation.
+func (c *Reddit) UnhighlightModMail(conversationID string) error {
+	return c.UnhighlightModMailContext(context.Background(), conversationID)
+}
+
+// UnhighlightModMailContext is like UnhighlightModMail, but with a context.
+func (c *Reddit) UnhighlightModMailContext(ctx context.Context, conversationID string) error {
+	return c.modMailAction(ctx, "DELETE", conversationID, "highlight", nil)
+}
+
+// MuteModMailUser mutes the participant of a modmail conversation for hours. reddit only
+// allows 72, 168 or 672 (3, 7 or 28 days), other durations return an error without a request.
+func (c *Reddit) MuteModMailUser(conversationID string, hours int) error {
+	return c.MuteModMailUserContext(context.Background(), conversationID, hours)
+}
+
+// MuteModMailUserContext is like MuteModMailUser, but with a context.
+func (c *Reddit) MuteModMailUserContext(ctx context.Context, conversationID string, hours int) error {
+	if hours != 72 && hours != 168 && hours != 672 {
+		return fmt.Errorf("mute duration must be 72, 168 or 672 hours, got %d", hours)
+	}
+	return c.modMailAction(ctx, "POST", conversationID, "mute", map[string]string{
+		"num_hours": strconv.Itoa(hours),
+	})
+}
+
+// UnmuteModMailUser unmutes the participant of a modmail conversation.
+func (c *Reddit) UnmuteModMailUser(conversationID string) error {
+	return c.UnmuteModMailUserContext(context.Background(), conversationID)
+}
+
+// UnmuteModMailUserContext is like UnmuteModMailUser, but with a context.
+func (c *Reddit) UnmuteModMailUserContext(ctx context.Context, conversationID string) error {
+	return c.modMailAction(ctx, "POST", conversationID, "unmute", nil)
+}
+
+// TempBanModMailUser bans the participant of a modmail conversation from the subreddit
+// for 1 to 999 days. Use BanWithOptions for permanent bans.
+func (c *Reddit) TempBanModMailUser(conversationID string, days int) error {
+	return c.TempBanModMailUserContext(context.Background(), conversationID, days)
+}
+
+// TempBanModMailUserContext is like TempBanModMailUser, but with a context.
+func (c *Reddit) TempBanModMailUserContext(ctx context.Context, conversationID string, days int) error {
+	if days < 1 || days > 999 {
+		return fmt.Errorf("ban duration must be between 1 and 999 days, got %d", days)
+	}
+	return c.modMailAction(ctx, "POST", conversationID, "temp_ban", map[string]string{
+		"duration": strconv.Itoa(days),
+	})
+}
+
+// UnbanModMailUser unbans the participant of a modmail conversation from the subreddit.
+func (c *Reddit) UnbanModMailUser(conversationID string) error {
+	return c.UnbanModMailUserContext(context.Background(), conversationID)
+}
+
+// UnbanModMailUserContext is like UnbanModMailUser, but with a context.
+func (c *Reddit) UnbanModMailUserContext(ctx context.Context, conversationID string) error {
+	return c.modMailAction(ctx, "POST", conversationID, "unban", nil)
+}
+
+// ReadModMail marks modmail conversations as read.
+func (c *Reddit) ReadModMail(conversationIDs ...string) error {
+	return c.ReadModMailContext(context.Background(), conversationIDs...)
+}
+
+// ReadModMailContext is like ReadModMail, but with a context.
+func (c *Reddit) ReadModMailContext(ctx context.Context, conversationIDs ...string) error {
+	target := c.endpoints.OAuth + "/api/mod/conversations/read"
+	_, err := c.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"conversationIds": strings.Join(conversationIDs, ","),
+	})
+	return err
+}
+
+// UnreadModMail marks modmail conversations as unread.
+func (c *Reddit) UnreadModMail(conversationIDs ...string) error {
+	return c.UnreadModMailContext(context.Background(), conversationIDs...)
+}
+
+// UnreadModMailContext is like UnreadModMail, but with a context.
+func (c *Reddit) UnreadModMailContext(ctx context.Context, conversationIDs ...string) error {
+	target := c.endpoints.OAuth + "/api/mod/conversations/unread"
+	_, err := c.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"conversationIds": strings.Join(conversationIDs, ","),
+	})
+	return err
+}
+
+// ModMailUnreadCount returns the number of unread modmail conversations in all subreddits you moderate.
+func (c *Reddit) ModMailUnreadCount() (*models.ModmailUnreadCount, error) {
+	return c.ModMailUnreadCountContext(context.Background())
+}
+
+// ModMailUnreadCountContext is like ModMailUnreadCount, but with a context.
+func (c *Reddit) ModMailUnreadCountContext(ctx context.Context) (*models.ModmailUnreadCount, error) {
+	target := c.endpoints.OAuth + "/api/mod/conversations/unread/count"
+	ans, err := c.MiraRequestContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.ModmailUnreadCount{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// modMailAction calls an action (archive, mute, ...) on a modmail conversation.
+func (c *Reddit) modMailAction(ctx context.Context, method string, conversationID string, action string, params map[string]string) error {
+	target := c.endpoints.OAuth + "/api/mod/conversations/" + conversationID + "/" + action
+	_, err := c.MiraRequestContext(ctx, method, target, params)
+	return err
+}
+
+// modMailConversation sends a request that answers with a modmail conversation.
+func (c *Reddit) modMailConversation(ctx context.Context, method string, target string, params map[string]string) (*models.NewModmailConversation, error) {
+	ans, err := c.MiraRequestContext(ctx, method, target, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.NewModmailConversation{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
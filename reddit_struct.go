@@ -18,6 +18,9 @@ type Credentials struct {
 	Password     string
 	UserAgent    string
 	RedirectURL  string
+	// Endpoints can be used to point mira to another server than reddit.com.
+	// Leave empty to use the defaults.
+	Endpoints Endpoints
 }
 
 // Reddit holds the connection to the API for a user. You can have multiple Reddit instances at the same time (see Example below).
@@ -29,6 +32,12 @@ type Credentials struct {
 //  reddit.Subreddit("iama").Submit("I just did a bot, AMA", "Hey all! I just created a bot. AMA.")
 //  reddit.Redditor("spez").Compose("Hi spez!", "Hi spez how are you?")
 //
+// Queueing an object returns an independent Queued handle, so a single Reddit instance
+// can be shared by as many goroutines as you like.
+//
+// Every method making a request also has a variant with a "Context" suffix (i.e. PostsContext)
+// that takes a context.Context as first argument, which can be used to cancel calls or set deadlines.
+//
 // Working with submissions
 //
 // If you call a function that returns a post or comment, you will receive an object that confirms to the models.Submission interface.
@@ -46,16 +55,29 @@ type Reddit struct {
 	TokenExpiry time.Time
 	UserAgent   string
 	ctx         context.Context
+	limiter     rateLimiter
+	endpoints   Endpoints
 
-	Chain  chan *chainVals
 	Values redditVals
 }
 
 type redditVals struct {
 	GetSubmissionFromCommentTries int
+	// Retry defines how failed requests are retried.
+	Retry RetryPolicy
 }
 
-type chainVals struct {
-	Name string
-	Type models.RedditKind
+// Queued is a handle to a reddit object (Subreddit, Post, Comment, Redditor or Me)
+// that actions can be performed on. It is returned by the queueing methods of Reddit
+// (Subreddit(), Post(), ...) and carries its own target, so it is safe to create and
+// use handles from multiple goroutines sharing the same Reddit instance.
+// A Queued handle is immutable and can be reused for as many calls as you like:
+//  sub := reddit.Subreddit("iama")
+//  posts, _ := sub.Posts("new", "all", 10)
+//  comments, _ := sub.Comments("new", "all", 10)
+type Queued struct {
+	*Reddit
+	name       string
+	kind       models.RedditKind
+	streamOpts StreamOptions
 }
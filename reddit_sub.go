@@ -1,6 +1,7 @@
 package mira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -8,9 +9,9 @@ import (
 	"github.com/ttgmpsn/mira/models"
 )
 
-func (c *Reddit) getSubreddit(name string) (*models.Subreddit, error) {
-	target := RedditOauth + "/r/" + name + "/about"
-	ans, err := c.MiraRequest("GET", target, nil)
+func (c *Reddit) getSubreddit(ctx context.Context, name string) (*models.Subreddit, error) {
+	target := c.endpoints.OAuth + "/r/" + name + "/about"
+	ans, err := c.MiraRequestContext(ctx, "GET", target, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -32,9 +33,9 @@ func (c *Reddit) getSubreddit(name string) (*models.Subreddit, error) {
 // Time options: "all", "year", "month", "week", "day", "hour"
 //
 // Limit is any numerical value, so 0 <= limit <= 100
-func (c *Reddit) getSubredditPosts(sr string, sort string, tdur string, limit int) ([]*models.Post, error) {
-	target := RedditOauth + "/r/" + sr + "/" + sort + ".json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getSubredditPosts(ctx context.Context, sr string, sort string, tdur string, limit int) ([]*models.Post, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/" + sort + ".json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit": strconv.Itoa(limit),
 		"t":     tdur,
 	})
@@ -52,9 +53,9 @@ func (c *Reddit) getSubredditPosts(sr string, sort string, tdur string, limit in
 	return ret, nil
 }
 
-func (c *Reddit) getSubredditComments(sr string, sort string, tdur string, limit int) ([]*models.Comment, error) {
-	target := RedditOauth + "/r/" + sr + "/comments.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getSubredditComments(ctx context.Context, sr string, sort string, tdur string, limit int) ([]*models.Comment, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/comments.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"sort":  sort,
 		"limit": strconv.Itoa(limit),
 		"t":     tdur,
@@ -81,9 +82,9 @@ func (c *Reddit) getSubredditComments(sr string, sort string, tdur string, limit
 // Limit is any numerical value, so 0 <= limit <= 100
 //
 // Anchor options are submissions full thing, for example: t3_bqqwm3
-func (c *Reddit) getSubredditPostsAfter(sr string, last models.RedditID, limit int) ([]*models.Post, error) {
-	target := RedditOauth + "/r/" + sr + "/new.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getSubredditPostsAfter(ctx context.Context, sr string, last models.RedditID, limit int) ([]*models.Post, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/new.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit":  strconv.Itoa(limit),
 		"before": string(last),
 	})
@@ -101,9 +102,9 @@ func (c *Reddit) getSubredditPostsAfter(sr string, last models.RedditID, limit i
 	return ret, nil
 }
 
-func (c *Reddit) getSubredditCommentsAfter(sr string, sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
-	target := RedditOauth + "/r/" + sr + "/comments.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getSubredditCommentsAfter(ctx context.Context, sr string, sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/comments.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"sort":   sort,
 		"limit":  strconv.Itoa(limit),
 		"before": string(last),
@@ -122,15 +123,20 @@ func (c *Reddit) getSubredditCommentsAfter(sr string, sort string, last models.R
 	return ret, nil
 }
 
-// UserFlair assigns a specific flair to a user on the last queued object.
+// UserFlair assigns a specific flair to a user on the queued object.
 // Valid objects: Subreddit
-func (c *Reddit) UserFlair(user, text string) error {
-	name, _, err := c.checkType(models.KSubreddit)
+func (q Queued) UserFlair(user, text string) error {
+	return q.UserFlairContext(context.Background(), user, text)
+}
+
+// UserFlairContext is like UserFlair, but with a context.
+func (q Queued) UserFlairContext(ctx context.Context, user, text string) error {
+	name, _, err := q.checkType(models.KSubreddit)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/r/" + name + "/api/flair"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/r/" + name + "/api/flair"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"name":     user,
 		"text":     text,
 		"api_type": "json",
@@ -138,62 +144,20 @@ func (c *Reddit) UserFlair(user, text string) error {
 	return err
 }
 
-// Wiki returns a wiki page from last queued object.
-// Valid objects: Subreddit
-func (c *Reddit) Wiki(page string) (*models.Wiki, error) {
-	sr, ttype := c.getQueue()
-	if ttype != models.KSubreddit {
-		return nil, fmt.Errorf("'%s' type does not have an option for wiki", ttype)
-	}
-
-	target := RedditOauth + "/r/" + sr + "/wiki/" + page + ".json"
-	ans, err := c.MiraRequest("GET", target, map[string]string{})
-	if err != nil {
-		return nil, err
-	}
-	ret := &models.Response{}
-	if err := json.Unmarshal([]byte(ans), ret); err != nil {
-		return nil, err
-	}
-
-	wiki, ok := ret.Data.(*models.Wiki)
-	if !ok {
-		return nil, fmt.Errorf("couldn't convert to Wiki struct")
-	}
-
-	return wiki, nil
-}
-
-// EditWiki edits/creates a wiki page from last queued object.
+// Stylesheet returns the stylesheet & images from the queued object.
 // Valid objects: Subreddit
-func (c *Reddit) EditWiki(page, content, reason string) error {
-	sr, ttype := c.getQueue()
-	if ttype != models.KSubreddit {
-		return fmt.Errorf("'%s' type does not have an option for editwiki", ttype)
-	}
-
-	target := RedditOauth + "/r/" + sr + "/api/wiki/edit"
-	_, err := c.MiraRequest("POST", target, map[string]string{
-		"content": content,
-		"page":    page,
-		"reason":  reason,
-	})
-
-	// API returns {}
-
-	return err
+func (q Queued) Stylesheet() (*models.Stylesheet, error) {
+	return q.StylesheetContext(context.Background())
 }
 
-// Stylesheet returns the stylesheet & images from last queued object.
-// Valid objects: Subreddit
-func (c *Reddit) Stylesheet() (*models.Stylesheet, error) {
-	sr, ttype := c.getQueue()
-	if ttype != models.KSubreddit {
-		return nil, fmt.Errorf("'%s' type does not have an option for stylesheet", ttype)
+// StylesheetContext is like Stylesheet, but with a context.
+func (q Queued) StylesheetContext(ctx context.Context) (*models.Stylesheet, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for stylesheet", q.kind)
 	}
 
-	target := RedditOauth + "/r/" + sr + "/about/stylesheet.json"
-	ans, err := c.MiraRequest("GET", target, map[string]string{})
+	target := q.endpoints.OAuth + "/r/" + q.name + "/about/stylesheet.json"
+	ans, err := q.MiraRequestContext(ctx, "GET", target, map[string]string{})
 	if err != nil {
 		return nil, err
 	}
@@ -1,17 +1,19 @@
 package mira
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ttgmpsn/mira/models"
 )
 
-func (c *Reddit) getPost(id models.RedditID) (*models.Post, error) {
-	target := RedditOauth + "/api/info.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getPost(ctx context.Context, id models.RedditID) (*models.Post, error) {
+	target := c.endpoints.OAuth + "/api/info.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"id": string(id),
 	})
 	if err != nil {
@@ -30,9 +32,9 @@ func (c *Reddit) getPost(id models.RedditID) (*models.Post, error) {
 	return post, nil
 }
 
-func (c *Reddit) getComment(id models.RedditID) (*models.Comment, error) {
-	target := RedditOauth + "/api/info.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getComment(ctx context.Context, id models.RedditID) (*models.Comment, error) {
+	target := c.endpoints.OAuth + "/api/info.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"id": string(id),
 	})
 	if err != nil {
@@ -51,12 +53,8 @@ func (c *Reddit) getComment(id models.RedditID) (*models.Comment, error) {
 	return comment, nil
 }
 
-func (c *Reddit) getPostComments(postID models.RedditID, sort string, tdur string, limit int) ([]*models.Comment, error) {
-	if postID.Type() != models.KPost {
-		return nil, errors.New("the passed ID is not a post")
-	}
-	target := fmt.Sprintf("%s/comments/%s", RedditOauth, postID[3:])
-	ans, err := c.MiraRequest("GET", target, map[string]string{
+func (c *Reddit) getPostComments(ctx context.Context, postID models.RedditID, sort string, tdur string, limit int) ([]*models.Comment, error) {
+	tree, err := c.getPostCommentTree(ctx, postID, map[string]string{
 		"sort":     sort,
 		"limit":    strconv.Itoa(limit),
 		"showmore": strconv.FormatBool(true),
@@ -65,36 +63,183 @@ func (c *Reddit) getPostComments(postID models.RedditID, sort string, tdur strin
 	if err != nil {
 		return nil, err
 	}
+	ret := tree.Replies.Comments
+	if ret == nil {
+		ret = []*models.Comment{}
+	}
+	return ret, nil
+}
+
+func (c *Reddit) getPostCommentTree(ctx context.Context, postID models.RedditID, params map[string]string) (*models.CommentTree, error) {
+	if postID.Type() != models.KPost {
+		return nil, errors.New("the passed ID is not a post")
+	}
+	target := fmt.Sprintf("%s/comments/%s", c.endpoints.OAuth, postID[3:])
+	ans, err := c.MiraRequestContext(ctx, "GET", target, params)
+	if err != nil {
+		return nil, err
+	}
 
 	rets := []*models.Response{}
 	if err = json.Unmarshal(ans, &rets); err != nil {
 		return nil, err
 	}
+	if len(rets) < 1 {
+		return nil, fmt.Errorf("no results")
+	}
+	list, ok := rets[0].Data.(*models.Listing)
+	if !ok || len(list.Children) < 1 {
+		return nil, fmt.Errorf("couldn't find post '%s' in response", postID)
+	}
+	post, ok := list.Children[0].Data.(*models.Post)
+	if !ok {
+		return nil, fmt.Errorf("provided ID '%s' is no valid post", postID)
+	}
 	if len(rets) < 2 {
 		// not two elements --> no comments
-		return []*models.Comment{}, nil
+		return models.NewCommentTree(post, nil), nil
 	}
-	list, ok := rets[1].Data.(*models.Listing)
+	list, ok = rets[1].Data.(*models.Listing)
 	if !ok {
 		return nil, fmt.Errorf("couldn't convert to Listing struct. Data has Kind '%s'", rets[1].Kind)
 	}
-	ret := []*models.Comment{}
-	for _, comment := range list.Children {
-		if c, ok := comment.Data.(*models.Comment); ok {
-			ret = append(ret, c)
+	return models.NewCommentTree(post, list), nil
+}
+
+// CommentTreeOptions defines how CommentTree loads the comments of a post.
+type CommentTreeOptions struct {
+	// Sort is one of "confidence", "top", "new", "controversial", "old", "qa". Empty uses the subreddits default.
+	Sort string
+	// Limit is the maximum number of comments in the first request. 0 uses reddits default.
+	Limit int
+	// MaxRequests is the number of additional requests used to expand "more" placeholders.
+	// 0 doesn't expand any placeholders, a negative value expands them until the whole thread is loaded.
+	MaxRequests int
+}
+
+// CommentTree returns the queued post with its comments as a tree.
+// Placeholders for comments reddit left out are expanded via /api/morechildren
+// until the whole thread is loaded or opts.MaxRequests is reached. Check
+// CommentTree.More() to see which placeholders are left.
+// Valid objects: Post
+func (q Queued) CommentTree(opts CommentTreeOptions) (*models.CommentTree, error) {
+	return q.CommentTreeContext(context.Background(), opts)
+}
+
+// CommentTreeContext is like CommentTree, but with a context.
+func (q Queued) CommentTreeContext(ctx context.Context, opts CommentTreeOptions) (*models.CommentTree, error) {
+	name, _, err := q.checkType(models.KPost)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"showmore": strconv.FormatBool(true),
+	}
+	if opts.Sort != "" {
+		params["sort"] = opts.Sort
+	}
+	if opts.Limit > 0 {
+		params["limit"] = strconv.Itoa(opts.Limit)
+	}
+	tree, err := q.getPostCommentTree(ctx, models.RedditID(name), params)
+	if err != nil {
+		return nil, err
+	}
+	return tree, q.expandCommentTree(ctx, tree, opts)
+}
+
+// expandCommentTree loads the comments behind the placeholders in tree.
+func (c *Reddit) expandCommentTree(ctx context.Context, tree *models.CommentTree, opts CommentTreeOptions) error {
+	tried := map[*models.More]bool{}
+	for n := 0; opts.MaxRequests < 0 || n < opts.MaxRequests; n++ {
+		var m *models.More
+		for _, v := range tree.More() {
+			if !tried[v] {
+				m = v
+				break
+			}
+		}
+		if m == nil {
+			return nil
+		}
+		tried[m] = true
+
+		if m.IsContinueThread() {
+			parent := tree.Find(m.ParentID)
+			if parent == nil {
+				continue
+			}
+			params := map[string]string{"comment": parent.ID}
+			if opts.Sort != "" {
+				params["sort"] = opts.Sort
+			}
+			sub, err := c.getPostCommentTree(ctx, tree.Post.Name, params)
+			if err != nil {
+				return err
+			}
+			if subParent := sub.Find(m.ParentID); subParent != nil {
+				tree.RemoveMore(m)
+				parent.Replies = subParent.Replies
+			}
+			continue
+		}
+
+		ids := m.Children
+		if len(ids) > 100 {
+			ids = ids[:100]
+		}
+		things, err := c.moreChildren(ctx, tree.Post.Name, ids, opts.Sort)
+		if err != nil {
+			return err
 		}
+		tree.RemoveMore(m)
+		if len(m.Children) > len(ids) {
+			rest := *m
+			rest.Children = m.Children[len(ids):]
+			rest.Count -= len(ids)
+			tree.Insert([]models.RedditElement{{Kind: models.KMore, Data: &rest}})
+		}
+		tree.Insert(things)
 	}
-	return ret, nil
+	return nil
+}
+
+// moreChildren fetches up to 100 comments of a post by their IDs.
+func (c *Reddit) moreChildren(ctx context.Context, postID models.RedditID, ids []string, sort string) ([]models.RedditElement, error) {
+	params := map[string]string{
+		"link_id":        string(postID),
+		"children":       strings.Join(ids, ","),
+		"limit_children": strconv.FormatBool(false),
+		"api_type":       "json",
+	}
+	if sort != "" {
+		params["sort"] = sort
+	}
+	target := c.endpoints.OAuth + "/api/morechildren"
+	ans, err := c.MiraRequestContext(ctx, "GET", target, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.CommentActionResponse{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret.JSON.Data.Things, nil
 }
 
-// GetParentPost returns the Post ID for the last queued object.
+// GetParentPost returns the Post ID for the queued object.
 // Valid objects: Comment
-func (c *Reddit) GetParentPost() (models.RedditID, error) {
-	name, _, err := c.checkType(models.KComment)
+func (q Queued) GetParentPost() (models.RedditID, error) {
+	return q.GetParentPostContext(context.Background())
+}
+
+// GetParentPostContext is like GetParentPost, but with a context.
+func (q Queued) GetParentPostContext(ctx context.Context) (models.RedditID, error) {
+	name, _, err := q.checkType(models.KComment)
 	if err != nil {
 		return "", err
 	}
-	info, err := c.getComment(models.RedditID(name))
+	info, err := q.getComment(ctx, models.RedditID(name))
 	if err != nil {
 		return "", err
 	}
@@ -102,13 +247,17 @@ func (c *Reddit) GetParentPost() (models.RedditID, error) {
 }
 
 // SubmissionInfo returns general information about the queued submission.
-func (c *Reddit) SubmissionInfo() (models.Submission, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+func (q Queued) SubmissionInfo() (models.Submission, error) {
+	return q.SubmissionInfoContext(context.Background())
+}
+
+// SubmissionInfoContext is like SubmissionInfo, but with a context.
+func (q Queued) SubmissionInfoContext(ctx context.Context) (models.Submission, error) {
+	switch q.kind {
 	case models.KPost:
-		return c.getPost(models.RedditID(name))
+		return q.getPost(ctx, models.RedditID(q.name))
 	case models.KComment:
-		return c.getComment(models.RedditID(name))
+		return q.getComment(ctx, models.RedditID(q.name))
 	default:
 		return nil, fmt.Errorf("returning type is not defined")
 	}
@@ -116,26 +265,37 @@ func (c *Reddit) SubmissionInfo() (models.Submission, error) {
 
 // SubmissionInfoID returns general information about the submission ID.
 func (c *Reddit) SubmissionInfoID(name models.RedditID) (models.Submission, error) {
+	return c.SubmissionInfoIDContext(context.Background(), name)
+}
+
+// SubmissionInfoIDContext is like SubmissionInfoID, but with a context.
+func (c *Reddit) SubmissionInfoIDContext(ctx context.Context, name models.RedditID) (models.Submission, error) {
 	switch name.Type() {
 	case models.KPost:
-		return c.getPost(name)
+		return c.getPost(ctx, name)
 	case models.KComment:
-		return c.getComment(name)
+		return c.getComment(ctx, name)
 	default:
 		return nil, fmt.Errorf("returning type is not defined")
 	}
 }
 
-// Submit submits a new Post to the last queued object.
+// Submit submits a new Post to the queued object.
+// If reddit rejects the post, a *ValidationError is returned.
 // Valid objects: Subreddit
-func (c *Reddit) Submit(title string, text string) (*models.PostActionResponse, error) {
+func (q Queued) Submit(title string, text string) (*models.PostActionResponse, error) {
+	return q.SubmitContext(context.Background(), title, text)
+}
+
+// SubmitContext is like Submit, but with a context.
+func (q Queued) SubmitContext(ctx context.Context, title string, text string) (*models.PostActionResponse, error) {
 	ret := &models.PostActionResponse{}
-	name, _, err := c.checkType(models.KSubreddit)
+	name, _, err := q.checkType(models.KSubreddit)
 	if err != nil {
 		return nil, err
 	}
-	target := RedditOauth + "/api/submit"
-	ans, err := c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/submit"
+	ans, err := q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"title":    title,
 		"sr":       name,
 		"text":     text,
@@ -143,80 +303,561 @@ func (c *Reddit) Submit(title string, text string) (*models.PostActionResponse,
 		"resubmit": "true",
 		"api_type": "json",
 	})
-	json.Unmarshal(ans, ret)
-	return ret, err
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
-// Reply adds a comment to the last queued object.
-// Valid objects: Comment, Post
-func (c *Reddit) Reply(text string) (*models.CommentActionResponse, error) {
-	name, _, err := c.checkType(models.KComment, models.KPost)
+// SubmitOptions describe a new post. Besides Title, set at most one of URL, Crosspost,
+// Images, Video or Poll. If none is set, a text post is created.
+type SubmitOptions struct {
+	Title string
+	// Text is the body of text & poll posts.
+	Text string
+	// URL creates a link post.
+	URL string
+	// Crosspost creates a crosspost of the given post (t3_XXXXX).
+	Crosspost models.RedditID
+	// Images are uploaded to reddit. A single image creates an image post, more create a gallery.
+	Images []MediaUpload
+	// Video is uploaded to reddit & creates a video post, with VideoPoster as its thumbnail.
+	Video       *MediaUpload
+	VideoPoster *MediaUpload
+	// Poll creates a poll post.
+	Poll *PollOptions
+
+	// FlairID is the ID of a flair template, FlairText overrides its text if it is editable.
+	FlairID   string
+	FlairText string
+	NSFW      bool
+	Spoiler   bool
+	// SendReplies sends replies to the inbox. Defaults to true.
+	SendReplies *bool
+	// Resubmit allows submitting a link that was posted to the subreddit before.
+	Resubmit bool
+}
+
+// PollOptions describe the poll of a poll post.
+type PollOptions struct {
+	// Options are the answers to choose from, 2 to 6.
+	Options []string
+	// Duration is the number of days the poll is open, 1 to 7.
+	Duration int
+}
+
+// kind returns the kind of post described by opts, or an error if it describes multiple.
+func (opts SubmitOptions) kind() (string, error) {
+	var kinds []string
+	if opts.URL != "" {
+		kinds = append(kinds, "link")
+	}
+	if opts.Crosspost != "" {
+		kinds = append(kinds, "crosspost")
+	}
+	if len(opts.Images) == 1 {
+		kinds = append(kinds, "image")
+	} else if len(opts.Images) > 1 {
+		kinds = append(kinds, "gallery")
+	}
+	if opts.Video != nil {
+		kinds = append(kinds, "video")
+	}
+	if opts.Poll != nil {
+		kinds = append(kinds, "poll")
+	}
+	switch len(kinds) {
+	case 0:
+		return "self", nil
+	case 1:
+		return kinds[0], nil
+	default:
+		return "", fmt.Errorf("a post can't be %s at the same time", strings.Join(kinds, " & "))
+	}
+}
+
+// SubmitWithOptions submits a new Post to the queued object. Files in opts are uploaded first.
+// If reddit rejects the post, a *ValidationError is returned.
+// Valid objects: Subreddit
+func (q Queued) SubmitWithOptions(opts SubmitOptions) (*models.SubmitResult, error) {
+	return q.SubmitWithOptionsContext(context.Background(), opts)
+}
+
+// SubmitWithOptionsContext is like SubmitWithOptions, but with a context.
+func (q Queued) SubmitWithOptionsContext(ctx context.Context, opts SubmitOptions) (*models.SubmitResult, error) {
+	name, _, err := q.checkType(models.KSubreddit)
 	if err != nil {
 		return nil, err
 	}
-	return c.ReplyWithID(name, text)
+	kind, err := opts.kind()
+	if err != nil {
+		return nil, err
+	}
+	sendReplies := opts.SendReplies == nil || *opts.SendReplies
+
+	switch kind {
+	case "gallery":
+		return q.submitGallery(ctx, name, opts, sendReplies)
+	case "poll":
+		return q.submitPoll(ctx, name, opts, sendReplies)
+	}
+
+	params := map[string]string{
+		"title":       opts.Title,
+		"sr":          name,
+		"kind":        kind,
+		"nsfw":        strconv.FormatBool(opts.NSFW),
+		"spoiler":     strconv.FormatBool(opts.Spoiler),
+		"sendreplies": strconv.FormatBool(sendReplies),
+		"resubmit":    strconv.FormatBool(opts.Resubmit),
+		"api_type":    "json",
+	}
+	if opts.FlairID != "" {
+		params["flair_id"] = opts.FlairID
+	}
+	if opts.FlairText != "" {
+		params["flair_text"] = opts.FlairText
+	}
+	switch kind {
+	case "self":
+		params["text"] = opts.Text
+	case "link":
+		params["url"] = opts.URL
+	case "crosspost":
+		if !strings.HasPrefix(string(opts.Crosspost), string(models.KPost)+"_") {
+			return nil, fmt.Errorf("'%s' is no post that can be crossposted", opts.Crosspost)
+		}
+		params["crosspost_fullname"] = string(opts.Crosspost)
+	case "image":
+		asset, err := q.UploadMediaContext(ctx, opts.Images[0])
+		if err != nil {
+			return nil, err
+		}
+		params["url"] = asset.URL
+	case "video":
+		if opts.VideoPoster == nil {
+			return nil, fmt.Errorf("video posts need a VideoPoster")
+		}
+		asset, err := q.UploadMediaContext(ctx, *opts.Video)
+		if err != nil {
+			return nil, err
+		}
+		poster, err := q.UploadMediaContext(ctx, *opts.VideoPoster)
+		if err != nil {
+			return nil, err
+		}
+		params["url"] = asset.URL
+		params["video_poster_url"] = poster.URL
+	}
+
+	target := q.endpoints.OAuth + "/api/submit"
+	ans, err := q.MiraRequestContext(ctx, "POST", target, params)
+	if err != nil {
+		return nil, err
+	}
+	return parseSubmitResult(ans)
+}
+
+// submitGallery uploads the images of opts & creates a gallery post of them.
+func (c *Reddit) submitGallery(ctx context.Context, sr string, opts SubmitOptions, sendReplies bool) (*models.SubmitResult, error) {
+	type galleryItem struct {
+		Caption     string `json:"caption"`
+		OutboundURL string `json:"outbound_url"`
+		MediaID     string `json:"media_id"`
+	}
+	items := make([]galleryItem, 0, len(opts.Images))
+	for _, img := range opts.Images {
+		asset, err := c.UploadMediaContext(ctx, img)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, galleryItem{Caption: img.Caption, OutboundURL: img.OutboundURL, MediaID: asset.ID})
+	}
+	target := c.endpoints.OAuth + "/api/submit_gallery_post.json"
+	ans, err := c.miraRequestJSON(ctx, "POST", target, map[string]interface{}{
+		"sr":              sr,
+		"title":           opts.Title,
+		"items":           items,
+		"nsfw":            opts.NSFW,
+		"spoiler":         opts.Spoiler,
+		"sendreplies":     sendReplies,
+		"flair_id":        opts.FlairID,
+		"flair_text":      opts.FlairText,
+		"show_error_list": true,
+		"api_type":        "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseSubmitResult(ans)
+}
+
+// submitPoll creates a poll post.
+func (c *Reddit) submitPoll(ctx context.Context, sr string, opts SubmitOptions, sendReplies bool) (*models.SubmitResult, error) {
+	if len(opts.Poll.Options) < 2 || len(opts.Poll.Options) > 6 {
+		return nil, fmt.Errorf("polls need 2 to 6 options, got %d", len(opts.Poll.Options))
+	}
+	if opts.Poll.Duration < 1 || opts.Poll.Duration > 7 {
+		return nil, fmt.Errorf("polls can be open for 1 to 7 days, got %d", opts.Poll.Duration)
+	}
+	target := c.endpoints.OAuth + "/api/submit_poll_post.json"
+	ans, err := c.miraRequestJSON(ctx, "POST", target, map[string]interface{}{
+		"sr":          sr,
+		"title":       opts.Title,
+		"text":        opts.Text,
+		"options":     opts.Poll.Options,
+		"duration":    opts.Poll.Duration,
+		"nsfw":        opts.NSFW,
+		"spoiler":     opts.Spoiler,
+		"sendreplies": sendReplies,
+		"flair_id":    opts.FlairID,
+		"flair_text":  opts.FlairText,
+		"api_type":    "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseSubmitResult(ans)
+}
+
+// parseSubmitResult reads the answer of the submit endpoints.
+// Galleries & polls return the full ID in "id", normal posts return it in "name".
+func parseSubmitResult(ans []byte) (*models.SubmitResult, error) {
+	resp := struct {
+		JSON struct {
+			Data models.SubmitResult `json:"data"`
+		} `json:"json"`
+	}{}
+	if err := json.Unmarshal(ans, &resp); err != nil {
+		return nil, err
+	}
+	ret := &resp.JSON.Data
+	if strings.HasPrefix(ret.ID, string(models.KPost)+"_") {
+		ret.Name = models.RedditID(ret.ID)
+	}
+	if ret.Name != "" {
+		ret.ID = strings.TrimPrefix(string(ret.Name), string(models.KPost)+"_")
+	}
+	return ret, nil
+}
+
+// Reply adds a comment to the queued object, or answers a message.
+// Valid objects: Comment, Post, Message
+func (q Queued) Reply(text string) (*models.CommentActionResponse, error) {
+	return q.ReplyContext(context.Background(), text)
+}
+
+// ReplyContext is like Reply, but with a context.
+func (q Queued) ReplyContext(ctx context.Context, text string) (*models.CommentActionResponse, error) {
+	name, _, err := q.checkType(models.KComment, models.KPost, models.KMessage)
+	if err != nil {
+		return nil, err
+	}
+	return q.ReplyWithIDContext(ctx, name, text)
 }
 
 // ReplyWithID adds a comment to the given thing id, without it needing to be queued up.
+// If reddit rejects the comment, a *ValidationError is returned.
 func (c *Reddit) ReplyWithID(name, text string) (*models.CommentActionResponse, error) {
+	return c.ReplyWithIDContext(context.Background(), name, text)
+}
+
+// ReplyWithIDContext is like ReplyWithID, but with a context.
+func (c *Reddit) ReplyWithIDContext(ctx context.Context, name, text string) (*models.CommentActionResponse, error) {
 	ret := &models.CommentActionResponse{}
-	target := RedditOauth + "/api/comment"
-	ans, err := c.MiraRequest("POST", target, map[string]string{
+	target := c.endpoints.OAuth + "/api/comment"
+	ans, err := c.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"text":     text,
 		"thing_id": name,
 		"api_type": "json",
 	})
-	json.Unmarshal(ans, ret)
-	return ret, err
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
-// Delete the last queued object.
+// Delete the queued object.
 // Valid objects: Comment, Post
-func (c *Reddit) Delete() error {
-	name, _, err := c.checkType(models.KComment, models.KPost)
+func (q Queued) Delete() error {
+	return q.DeleteContext(context.Background())
+}
+
+// DeleteContext is like Delete, but with a context.
+func (q Queued) DeleteContext(ctx context.Context) error {
+	name, _, err := q.checkType(models.KComment, models.KPost)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/del"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/del"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"id":       name,
 		"api_type": "json",
 	})
 	return err
 }
 
-// Edit the last queued object.
+// Edit the queued object.
 // Valid objects: Comment, Post
-func (c *Reddit) Edit(text string) (*models.Comment, error) {
-	ret := &models.Comment{}
-	name, _, err := c.checkType(models.KComment, models.KPost)
+func (q Queued) Edit(text string) (*models.Comment, error) {
+	return q.EditContext(context.Background(), text)
+}
+
+// EditContext is like Edit, but with a context.
+func (q Queued) EditContext(ctx context.Context, text string) (*models.Comment, error) {
+	name, _, err := q.checkType(models.KComment, models.KPost)
 	if err != nil {
 		return nil, err
 	}
-	target := RedditOauth + "/api/editusertext"
-	ans, err := c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/editusertext"
+	ans, err := q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"text":     text,
 		"thing_id": name,
 		"api_type": "json",
 	})
-	// :TODO: check reply type
-	fmt.Println(string(ans))
-	json.Unmarshal(ans, ret)
-	return ret, err
+	if err != nil {
+		return nil, err
+	}
+	resp := &models.CommentActionResponse{}
+	if err := json.Unmarshal(ans, resp); err != nil {
+		return nil, err
+	}
+	ret := &models.Comment{}
+	if len(resp.JSON.Data.Things) > 0 {
+		if c, ok := resp.JSON.Data.Things[0].Data.(*models.Comment); ok {
+			ret = c
+		}
+	}
+	return ret, nil
 }
 
-// SelectFlair for the last queued object.
+// SelectFlair for the queued object.
 // Valid objects: Post
-func (c *Reddit) SelectFlair(text string) error {
-	name, _, err := c.checkType(models.KPost)
+func (q Queued) SelectFlair(text string) error {
+	return q.SelectFlairContext(context.Background(), text)
+}
+
+// SelectFlairContext is like SelectFlair, but with a context.
+func (q Queued) SelectFlairContext(ctx context.Context, text string) error {
+	name, _, err := q.checkType(models.KPost)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/selectflair"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/selectflair"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"link":     name,
 		"text":     text,
 		"api_type": "json",
 	})
 	return err
 }
+
+// Upvote the queued object.
+// Valid objects: Comment, Post
+func (q Queued) Upvote() error {
+	return q.UpvoteContext(context.Background())
+}
+
+// UpvoteContext is like Upvote, but with a context.
+func (q Queued) UpvoteContext(ctx context.Context) error {
+	return q.vote(ctx, 1)
+}
+
+// Downvote the queued object.
+// Valid objects: Comment, Post
+func (q Queued) Downvote() error {
+	return q.DownvoteContext(context.Background())
+}
+
+// DownvoteContext is like Downvote, but with a context.
+func (q Queued) DownvoteContext(ctx context.Context) error {
+	return q.vote(ctx, -1)
+}
+
+// Unvote removes your vote from the queued object.
+// Valid objects: Comment, Post
+func (q Queued) Unvote() error {
+	return q.UnvoteContext(context.Background())
+}
+
+// UnvoteContext is like Unvote, but with a context.
+func (q Queued) UnvoteContext(ctx context.Context) error {
+	return q.vote(ctx, 0)
+}
+
+func (q Queued) vote(ctx context.Context, dir int) error {
+	name, _, err := q.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	target := q.endpoints.OAuth + "/api/vote"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"id":  name,
+		"dir": strconv.Itoa(dir),
+	})
+	return err
+}
+
+// Save the queued object. category is optional, and needs reddit premium.
+// Valid objects: Comment, Post
+func (q Queued) Save(category string) error {
+	return q.SaveContext(context.Background(), category)
+}
+
+// SaveContext is like Save, but with a context.
+func (q Queued) SaveContext(ctx context.Context, category string) error {
+	name, _, err := q.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	params := map[string]string{"id": name}
+	if category != "" {
+		params["category"] = category
+	}
+	target := q.endpoints.OAuth + "/api/save"
+	_, err = q.MiraRequestContext(ctx, "POST", target, params)
+	return err
+}
+
+// Unsave the queued object.
+// Valid objects: Comment, Post
+func (q Queued) Unsave() error {
+	return q.UnsaveContext(context.Background())
+}
+
+// UnsaveContext is like Unsave, but with a context.
+func (q Queued) UnsaveContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/unsave", models.KComment, models.KPost)
+}
+
+// Hide the queued object from your listings.
+// Valid objects: Post
+func (q Queued) Hide() error {
+	return q.HideContext(context.Background())
+}
+
+// HideContext is like Hide, but with a context.
+func (q Queued) HideContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/hide", models.KPost)
+}
+
+// Unhide the queued object.
+// Valid objects: Post
+func (q Queued) Unhide() error {
+	return q.UnhideContext(context.Background())
+}
+
+// UnhideContext is like Unhide, but with a context.
+func (q Queued) UnhideContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/unhide", models.KPost)
+}
+
+// MarkNSFW marks the queued object as NSFW.
+// Valid objects: Post
+func (q Queued) MarkNSFW() error {
+	return q.MarkNSFWContext(context.Background())
+}
+
+// MarkNSFWContext is like MarkNSFW, but with a context.
+func (q Queued) MarkNSFWContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/marknsfw", models.KPost)
+}
+
+// UnmarkNSFW removes the NSFW mark of the queued object.
+// Valid objects: Post
+func (q Queued) UnmarkNSFW() error {
+	return q.UnmarkNSFWContext(context.Background())
+}
+
+// UnmarkNSFWContext is like UnmarkNSFW, but with a context.
+func (q Queued) UnmarkNSFWContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/unmarknsfw", models.KPost)
+}
+
+// Spoiler marks the queued object as spoiler.
+// Valid objects: Post
+func (q Queued) Spoiler() error {
+	return q.SpoilerContext(context.Background())
+}
+
+// SpoilerContext is like Spoiler, but with a context.
+func (q Queued) SpoilerContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/spoiler", models.KPost)
+}
+
+// Unspoiler removes the spoiler mark of the queued object.
+// Valid objects: Post
+func (q Queued) Unspoiler() error {
+	return q.UnspoilerContext(context.Background())
+}
+
+// UnspoilerContext is like Unspoiler, but with a context.
+func (q Queued) UnspoilerContext(ctx context.Context) error {
+	return q.postAction(ctx, "/api/unspoiler", models.KPost)
+}
+
+// postAction posts the ID of the queued object to path, if it is one of rtype.
+func (q Queued) postAction(ctx context.Context, path string, rtype ...models.RedditKind) error {
+	name, _, err := q.checkType(rtype...)
+	if err != nil {
+		return err
+	}
+	_, err = q.MiraRequestContext(ctx, "POST", q.endpoints.OAuth+path, map[string]string{
+		"id": name,
+	})
+	return err
+}
+
+// ReportReason is the reason of a report. Set exactly one of the fields.
+type ReportReason struct {
+	// Rule is the short name of the subreddit rule that was broken.
+	Rule string
+	// Site is the reddit content policy that was broken, i.e. "This is spam".
+	Site string
+	// Other is a free-form reason, up to 100 characters.
+	Other string
+}
+
+// Report the queued object to the moderators.
+// If reddit rejects the report, a *ValidationError is returned.
+// Valid objects: Comment, Post
+func (q Queued) Report(reason ReportReason) error {
+	return q.ReportContext(context.Background(), reason)
+}
+
+// ReportContext is like Report, but with a context.
+func (q Queued) ReportContext(ctx context.Context, reason ReportReason) error {
+	name, _, err := q.checkType(models.KComment, models.KPost)
+	if err != nil {
+		return err
+	}
+	params := map[string]string{
+		"thing_id": name,
+		"api_type": "json",
+	}
+	switch {
+	case reason.Rule != "" && reason.Site == "" && reason.Other == "":
+		params["reason"] = reason.Rule
+		params["rule_reason"] = reason.Rule
+	case reason.Site != "" && reason.Rule == "" && reason.Other == "":
+		params["reason"] = reason.Site
+		params["site_reason"] = reason.Site
+	case reason.Other != "" && reason.Rule == "" && reason.Site == "":
+		if len(reason.Other) > 100 {
+			return fmt.Errorf("report reasons can be up to 100 characters long, got %d", len(reason.Other))
+		}
+		params["reason"] = "other"
+		params["other_reason"] = reason.Other
+	default:
+		return fmt.Errorf("a report needs exactly one reason")
+	}
+	target := q.endpoints.OAuth + "/api/report"
+	_, err = q.MiraRequestContext(ctx, "POST", target, params)
+	return err
+}
@@ -0,0 +1,187 @@
+package mira_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Queued handles used by different goroutines must not see each others targets.
+// Run with -race to check the handles don't share state.
+func TestQueuedConcurrent(t *testing.T) {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	srv.AddPost(&models.Post{Subreddit: "a", Title: "in a"})
+	srv.AddPost(&models.Post{ID: "x", Subreddit: "b", Title: "in b"})
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			posts, err := reddit.Subreddit("a").Posts("new", "all", 10)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(posts) != 1 || posts[0].Subreddit != "a" {
+				t.Errorf("Subreddit(a).Posts returned %d posts, want 1 from r/a", len(posts))
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			info, err := reddit.Post("t3_x").Info()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if info.GetID() != "t3_x" {
+				t.Errorf("Post(t3_x).Info returned %s", info.GetID())
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestCommentTreeMaxRequests(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		maxRequests int
+		// loaded is the number of comments in the tree, left the size of the placeholder left over
+		loaded, left int
+		// requests are the number of IDs sent to /api/morechildren with each request
+		requests []int
+	}{
+		{name: "none", maxRequests: 0, loaded: 1, left: 149},
+		// morechildren takes up to 100 IDs, the rest stays in a placeholder
+		{name: "one", maxRequests: 1, loaded: 101, left: 49, requests: []int{100}},
+		{name: "enough", maxRequests: 2, loaded: 150, requests: []int{100, 49}},
+		{name: "unlimited", maxRequests: -1, loaded: 150, requests: []int{100, 49}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := miratest.NewServer()
+			defer srv.Close()
+			reddit, err := srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			post := srv.AddPost(&models.Post{Subreddit: "test"})
+			for i := 0; i < 150; i++ {
+				srv.AddComment(&models.Comment{LinkID: post.Name})
+			}
+
+			tree, err := reddit.Post(string(post.Name)).CommentTree(mira.CommentTreeOptions{Limit: 1, MaxRequests: tc.maxRequests})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tree.Len() != tc.loaded {
+				t.Errorf("got %d comments, want %d", tree.Len(), tc.loaded)
+			}
+			more := tree.More()
+			if tc.left == 0 && len(more) != 0 {
+				t.Errorf("got %d placeholders, want none", len(more))
+			}
+			if tc.left > 0 {
+				if len(more) != 1 || more[0].Count != tc.left || len(more[0].Children) != tc.left || more[0].ParentID != post.Name {
+					t.Fatalf("got placeholders %+v, want one for the %d remaining comments", more, tc.left)
+				}
+			}
+
+			requests := srv.RequestsTo("/api/morechildren")
+			if len(requests) != len(tc.requests) {
+				t.Fatalf("sent %d requests to /api/morechildren, want %d", len(requests), len(tc.requests))
+			}
+			for i, r := range requests {
+				if ids := strings.Split(r.Form.Get("children"), ","); len(ids) != tc.requests[i] || r.Form.Get("link_id") != string(post.Name) {
+					t.Errorf("request %d: got %d IDs of %s, want %d", i, len(ids), r.Form.Get("link_id"), tc.requests[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCommentTreeContinueThread(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		maxRequests int
+		loaded      int
+		// focus are the indexes of the comments requested to continue the thread
+		focus []int
+	}{
+		// reddit sends 10 levels, the rest of the thread is behind a "continue this thread" link
+		{name: "none", maxRequests: 0, loaded: 10},
+		{name: "one", maxRequests: 1, loaded: 19, focus: []int{9}},
+		{name: "unlimited", maxRequests: -1, loaded: 25, focus: []int{9, 18}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := miratest.NewServer()
+			defer srv.Close()
+			reddit, err := srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			post := srv.AddPost(&models.Post{Subreddit: "test"})
+			// a thread of 25 comments, each replying to the previous one
+			thread := make([]*models.Comment, 25)
+			parent := post.Name
+			for i := range thread {
+				thread[i] = srv.AddComment(&models.Comment{LinkID: post.Name, ParentID: parent})
+				parent = thread[i].Name
+			}
+
+			tree, err := reddit.Post(string(post.Name)).CommentTree(mira.CommentTreeOptions{MaxRequests: tc.maxRequests})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tree.Len() != tc.loaded {
+				t.Errorf("got %d comments, want %d", tree.Len(), tc.loaded)
+			}
+			depth := -1
+			tree.Walk(func(c *models.Comment, d int) bool {
+				if c.Name != thread[d].Name {
+					t.Errorf("got %s at depth %d, want %s", c.Name, d, thread[d].Name)
+				}
+				depth = d
+				return true
+			})
+			if depth != tc.loaded-1 {
+				t.Errorf("the thread is %d levels deep, want %d", depth+1, tc.loaded)
+			}
+
+			// the link to the rest of the thread stays in the tree
+			more := tree.More()
+			if tc.loaded < len(thread) {
+				if len(more) != 1 || !more[0].IsContinueThread() || more[0].ParentID != thread[tc.loaded-1].Name {
+					t.Errorf("got placeholders %+v, want one to continue below %s", more, thread[tc.loaded-1].Name)
+				}
+			} else if len(more) != 0 {
+				t.Errorf("got %d placeholders, want none", len(more))
+			}
+
+			requests := srv.RequestsTo("/comments/" + post.ID)
+			if len(requests) != len(tc.focus)+1 {
+				t.Fatalf("sent %d requests for the comments, want %d", len(requests), len(tc.focus)+1)
+			}
+			for i, r := range requests[1:] {
+				if want := thread[tc.focus[i]].ID; r.Form.Get("comment") != want {
+					t.Errorf("request %d: continued below %q, want %s", i+1, r.Form.Get("comment"), want)
+				}
+			}
+			if n := len(srv.RequestsTo("/api/morechildren")); n != 0 {
+				t.Errorf("sent %d requests to /api/morechildren, want none", n)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package mira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -8,9 +9,9 @@ import (
 	"github.com/ttgmpsn/mira/models"
 )
 
-func (c *Reddit) getUser(name string) (*models.Redditor, error) {
-	target := RedditOauth + "/user/" + name + "/about"
-	ans, err := c.MiraRequest("GET", target, nil)
+func (c *Reddit) getUser(ctx context.Context, name string) (*models.Redditor, error) {
+	target := c.endpoints.OAuth + "/user/" + name + "/about"
+	ans, err := c.MiraRequestContext(ctx, "GET", target, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -25,9 +26,9 @@ func (c *Reddit) getUser(name string) (*models.Redditor, error) {
 	return user, nil
 }
 
-func (c *Reddit) getRedditorPosts(user string, sort string, tdur string, limit int) ([]*models.Post, error) {
-	target := RedditOauth + "/u/" + user + "/submitted/" + sort + ".json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getRedditorPosts(ctx context.Context, user string, sort string, tdur string, limit int) ([]*models.Post, error) {
+	target := c.endpoints.OAuth + "/u/" + user + "/submitted/" + sort + ".json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit": strconv.Itoa(limit),
 		"t":     tdur,
 	})
@@ -45,9 +46,9 @@ func (c *Reddit) getRedditorPosts(user string, sort string, tdur string, limit i
 	return ret, nil
 }
 
-func (c *Reddit) getRedditorPostsAfter(user string, last models.RedditID, limit int) ([]*models.Post, error) {
-	target := RedditOauth + "/u/" + user + "/submitted/new.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getRedditorPostsAfter(ctx context.Context, user string, last models.RedditID, limit int) ([]*models.Post, error) {
+	target := c.endpoints.OAuth + "/u/" + user + "/submitted/new.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit": strconv.Itoa(limit),
 		"after": string(last),
 	})
@@ -65,9 +66,9 @@ func (c *Reddit) getRedditorPostsAfter(user string, last models.RedditID, limit
 	return ret, nil
 }
 
-func (c *Reddit) getRedditorComments(user string, sort string, tdur string, limit int) ([]*models.Comment, error) {
-	target := RedditOauth + "/u/" + user + "/comments.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getRedditorComments(ctx context.Context, user string, sort string, tdur string, limit int) ([]*models.Comment, error) {
+	target := c.endpoints.OAuth + "/u/" + user + "/comments.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"sort":  sort,
 		"limit": strconv.Itoa(limit),
 		"t":     tdur,
@@ -86,9 +87,9 @@ func (c *Reddit) getRedditorComments(user string, sort string, tdur string, limi
 	return ret, nil
 }
 
-func (c *Reddit) getRedditorCommentsAfter(user string, sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
-	target := RedditOauth + "/u/" + user + "/comments.json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getRedditorCommentsAfter(ctx context.Context, user string, sort string, last models.RedditID, limit int) ([]*models.Comment, error) {
+	target := c.endpoints.OAuth + "/u/" + user + "/comments.json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"sort":  sort,
 		"limit": strconv.Itoa(limit),
 		"after": string(last),
@@ -107,9 +108,9 @@ func (c *Reddit) getRedditorCommentsAfter(user string, sort string, last models.
 	return ret, nil
 }
 
-func (c *Reddit) getRedditorSubmissions(user string, limit int) ([]models.Submission, error) {
-	target := RedditOauth + "/u/" + user + ".json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getRedditorSubmissions(ctx context.Context, user string, limit int) ([]models.Submission, error) {
+	target := c.endpoints.OAuth + "/u/" + user + ".json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit": strconv.Itoa(limit),
 	})
 	if err != nil {
@@ -126,9 +127,9 @@ func (c *Reddit) getRedditorSubmissions(user string, limit int) ([]models.Submis
 	return ret, nil
 }
 
-func (c *Reddit) getRedditorSubmissionsAfter(user string, last models.RedditID, limit int) ([]models.Submission, error) {
-	target := RedditOauth + "/u/" + user + ".json"
-	list, err := c.miraRequestListing("GET", target, map[string]string{
+func (c *Reddit) getRedditorSubmissionsAfter(ctx context.Context, user string, last models.RedditID, limit int) ([]models.Submission, error) {
+	target := c.endpoints.OAuth + "/u/" + user + ".json"
+	list, err := c.miraRequestListing(ctx, "GET", target, map[string]string{
 		"limit": strconv.Itoa(limit),
 		"after": string(last),
 	})
@@ -146,9 +147,9 @@ func (c *Reddit) getRedditorSubmissionsAfter(user string, last models.RedditID,
 	return ret, nil
 }
 
-func (c *Reddit) getMe() (*models.Me, error) {
-	target := RedditOauth + "/api/v1/me"
-	ans, err := c.MiraRequest("GET", target, nil)
+func (c *Reddit) getMe(ctx context.Context) (*models.Me, error) {
+	target := c.endpoints.OAuth + "/api/v1/me"
+	ans, err := c.MiraRequestContext(ctx, "GET", target, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -159,15 +160,20 @@ func (c *Reddit) getMe() (*models.Me, error) {
 	return ret, nil
 }
 
-// Compose writes a private message to the last queued object.
+// Compose writes a private message to the queued object.
 // Valid objects: Redditor
-func (c *Reddit) Compose(subject, text string) error {
-	name, _, err := c.checkType(models.KRedditor)
+func (q Queued) Compose(subject, text string) error {
+	return q.ComposeContext(context.Background(), subject, text)
+}
+
+// ComposeContext is like Compose, but with a context.
+func (q Queued) ComposeContext(ctx context.Context, subject, text string) error {
+	name, _, err := q.checkType(models.KRedditor)
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/compose"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/compose"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"subject":  subject,
 		"text":     text,
 		"to":       name,
@@ -176,47 +182,176 @@ func (c *Reddit) Compose(subject, text string) error {
 	return err
 }
 
-// ReadMessage marks a message for the last queued object as read.
+// ReadMessage marks a message for the queued object as read.
 // Valid objects: Me
-func (c *Reddit) ReadMessage(messageID string) error {
-	_, _, err := c.checkType("me")
+func (q Queued) ReadMessage(messageID string) error {
+	return q.ReadMessageContext(context.Background(), messageID)
+}
+
+// ReadMessageContext is like ReadMessage, but with a context.
+func (q Queued) ReadMessageContext(ctx context.Context, messageID string) error {
+	_, _, err := q.checkType("me")
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/read_message"
-	_, err = c.MiraRequest("POST", target, map[string]string{
+	target := q.endpoints.OAuth + "/api/read_message"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
 		"id": messageID,
 	})
 	return err
 }
 
-// ReadAllMessages marks all message for the last queued object as read.
+// ReadAllMessages marks all message for the queued object as read.
 // Valid objects: Me
-func (c *Reddit) ReadAllMessages() error {
-	_, _, err := c.checkType("me")
+func (q Queued) ReadAllMessages() error {
+	return q.ReadAllMessagesContext(context.Background())
+}
+
+// ReadAllMessagesContext is like ReadAllMessages, but with a context.
+func (q Queued) ReadAllMessagesContext(ctx context.Context) error {
+	_, _, err := q.checkType("me")
 	if err != nil {
 		return err
 	}
-	target := RedditOauth + "/api/read_all_messages"
-	_, err = c.MiraRequest("POST", target, nil)
+	target := q.endpoints.OAuth + "/api/read_all_messages"
+	_, err = q.MiraRequestContext(ctx, "POST", target, nil)
 	return err
 }
 
-// ListUnreadMessages for the last queued object.
+// ListUnreadMessages returns up to 100 unread messages of the queued object, without marking them as read.
+// Valid objects: Me
+func (q Queued) ListUnreadMessages() ([]*models.Message, error) {
+	return q.ListUnreadMessagesContext(context.Background())
+}
+
+// ListUnreadMessagesContext is like ListUnreadMessages, but with a context.
+func (q Queued) ListUnreadMessagesContext(ctx context.Context) ([]*models.Message, error) {
+	return q.MessagesContext(ctx, InboxUnread, 100)
+}
+
+// Inbox folders for Messages, MessagesAfter & IterMessages.
+const (
+	// InboxAll contains everything: private messages, comment replies, post replies & mentions.
+	InboxAll = "inbox"
+	// InboxUnread contains all unread items.
+	InboxUnread = "unread"
+	// InboxSent contains the private messages you sent.
+	InboxSent = "sent"
+	// InboxMessages contains private messages only.
+	InboxMessages = "messages"
+	// InboxMentions contains username mentions only.
+	InboxMentions = "mentions"
+	// InboxCommentReplies contains replies to your comments only.
+	InboxCommentReplies = "comments"
+	// InboxPostReplies contains replies to your posts only.
+	InboxPostReplies = "selfreply"
+)
+
+// Messages gets messages from an inbox folder of the queued object, i.e. InboxUnread.
+// Reading messages doesn't mark them as read.
 // Valid objects: Me
-func (c *Reddit) ListUnreadMessages() ([]*models.Comment, error) {
-	_, _, err := c.checkType("me")
+func (q Queued) Messages(where string, limit int) ([]*models.Message, error) {
+	return q.MessagesContext(context.Background(), where, limit)
+}
+
+// MessagesContext is like Messages, but with a context.
+func (q Queued) MessagesContext(ctx context.Context, where string, limit int) ([]*models.Message, error) {
+	if _, _, err := q.checkType("me"); err != nil {
+		return nil, err
+	}
+	return q.getMessages(ctx, where, map[string]string{
+		"limit": strconv.Itoa(limit),
+		"mark":  "false",
+	})
+}
+
+// MessagesAfter gets messages from an inbox folder of the queued object after a given message.
+// Valid objects: Me
+func (q Queued) MessagesAfter(where string, last models.RedditID, limit int) ([]*models.Message, error) {
+	return q.MessagesAfterContext(context.Background(), where, last, limit)
+}
+
+// MessagesAfterContext is like MessagesAfter, but with a context.
+func (q Queued) MessagesAfterContext(ctx context.Context, where string, last models.RedditID, limit int) ([]*models.Message, error) {
+	if _, _, err := q.checkType("me"); err != nil {
+		return nil, err
+	}
+	return q.getMessages(ctx, where, map[string]string{
+		"limit": strconv.Itoa(limit),
+		"mark":  "false",
+		"after": string(last),
+	})
+}
+
+func (c *Reddit) getMessages(ctx context.Context, where string, params map[string]string) ([]*models.Message, error) {
+	target := c.endpoints.OAuth + "/message/" + where
+	list, err := c.miraRequestListing(ctx, "GET", target, params)
 	if err != nil {
 		return nil, err
 	}
-	target := RedditOauth + "/message/unread"
-	ans, err := c.MiraRequest("GET", target, map[string]string{
-		"mark": "false",
+
+	ret := []*models.Message{}
+	for _, child := range list.Children {
+		if m, ok := child.Data.(*models.Message); ok {
+			ret = append(ret, m)
+		}
+	}
+	return ret, nil
+}
+
+// MarkRead marks the queued message as read.
+// Valid objects: Message
+func (q Queued) MarkRead() error {
+	return q.MarkReadContext(context.Background())
+}
+
+// MarkReadContext is like MarkRead, but with a context.
+func (q Queued) MarkReadContext(ctx context.Context) error {
+	name, _, err := q.checkType(models.KMessage)
+	if err != nil {
+		return err
+	}
+	target := q.endpoints.OAuth + "/api/read_message"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"id": name,
 	})
-	ret := []*models.Comment{}
+	return err
+}
+
+// MarkUnread marks the queued message as unread.
+// Valid objects: Message
+func (q Queued) MarkUnread() error {
+	return q.MarkUnreadContext(context.Background())
+}
+
+// MarkUnreadContext is like MarkUnread, but with a context.
+func (q Queued) MarkUnreadContext(ctx context.Context) error {
+	name, _, err := q.checkType(models.KMessage)
+	if err != nil {
+		return err
+	}
+	target := q.endpoints.OAuth + "/api/unread_message"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"id": name,
+	})
+	return err
+}
 
-	// :TODO: check reply type
-	fmt.Println(string(ans))
-	json.Unmarshal(ans, &ret)
-	return ret, err
+// Block blocks the author of the queued message.
+// Valid objects: Message
+func (q Queued) Block() error {
+	return q.BlockContext(context.Background())
+}
+
+// BlockContext is like Block, but with a context.
+func (q Queued) BlockContext(ctx context.Context) error {
+	name, _, err := q.checkType(models.KMessage)
+	if err != nil {
+		return err
+	}
+	target := q.endpoints.OAuth + "/api/block"
+	_, err = q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"id": name,
+	})
+	return err
 }
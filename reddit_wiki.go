@@ -0,0 +1,346 @@
+package mira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ttgmpsn/mira/models"
+)
+
+// Wiki returns a wiki page from the queued object.
+// Valid objects: Subreddit
+func (q Queued) Wiki(page string) (*models.Wiki, error) {
+	return q.WikiContext(context.Background(), page)
+}
+
+// WikiContext is like Wiki, but with a context.
+func (q Queued) WikiContext(ctx context.Context, page string) (*models.Wiki, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki", q.kind)
+	}
+	return q.getWiki(ctx, q.name, page, map[string]string{})
+}
+
+// WikiRevision returns a wiki page from the queued object as it was at the given revision.
+// Valid objects: Subreddit
+func (q Queued) WikiRevision(page, revisionID string) (*models.Wiki, error) {
+	return q.WikiRevisionContext(context.Background(), page, revisionID)
+}
+
+// WikiRevisionContext is like WikiRevision, but with a context.
+func (q Queued) WikiRevisionContext(ctx context.Context, page, revisionID string) (*models.Wiki, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki", q.kind)
+	}
+	return q.getWiki(ctx, q.name, page, map[string]string{"v": revisionID})
+}
+
+// WikiRevisionDiff returns a wiki page of the queued object as it was at revision to, with
+// DiffContent showing the changes since revision from.
+// Valid objects: Subreddit
+func (q Queued) WikiRevisionDiff(page, from, to string) (*models.Wiki, error) {
+	return q.WikiRevisionDiffContext(context.Background(), page, from, to)
+}
+
+// WikiRevisionDiffContext is like WikiRevisionDiff, but with a context.
+func (q Queued) WikiRevisionDiffContext(ctx context.Context, page, from, to string) (*models.Wiki, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki", q.kind)
+	}
+	return q.getWiki(ctx, q.name, page, map[string]string{"v": from, "v2": to})
+}
+
+func (c *Reddit) getWiki(ctx context.Context, sr, page string, params map[string]string) (*models.Wiki, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/wiki/" + page + ".json"
+	ans, err := c.MiraRequestContext(ctx, "GET", target, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.Response{}
+	if err := json.Unmarshal([]byte(ans), ret); err != nil {
+		return nil, err
+	}
+
+	wiki, ok := ret.Data.(*models.Wiki)
+	if !ok {
+		return nil, fmt.Errorf("couldn't convert to Wiki struct")
+	}
+
+	return wiki, nil
+}
+
+// WikiPages returns the names of all wiki pages of the queued object.
+// Valid objects: Subreddit
+func (q Queued) WikiPages() ([]string, error) {
+	return q.WikiPagesContext(context.Background())
+}
+
+// WikiPagesContext is like WikiPages, but with a context.
+func (q Queued) WikiPagesContext(ctx context.Context) ([]string, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki pages", q.kind)
+	}
+
+	target := q.endpoints.OAuth + "/r/" + q.name + "/wiki/pages"
+	ans, err := q.MiraRequestContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.Response{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+
+	pages, ok := ret.Data.(*[]string)
+	if !ok {
+		return nil, fmt.Errorf("couldn't convert to wiki page list")
+	}
+
+	return *pages, nil
+}
+
+// WikiEditOptions are the options for EditWikiWithOptions.
+type WikiEditOptions struct {
+	Content string
+	Reason  string
+	// Previous is the revision your edit is based on, i.e. Wiki.RevisionID. If set, the edit
+	// fails with a *WikiConflictError if someone else edited the page after that revision.
+	Previous string
+}
+
+// EditWiki edits/creates a wiki page from the queued object.
+// Valid objects: Subreddit
+func (q Queued) EditWiki(page, content, reason string) error {
+	return q.EditWikiContext(context.Background(), page, content, reason)
+}
+
+// EditWikiContext is like EditWiki, but with a context.
+func (q Queued) EditWikiContext(ctx context.Context, page, content, reason string) error {
+	return q.EditWikiWithOptionsContext(ctx, page, WikiEditOptions{Content: content, Reason: reason})
+}
+
+// EditWikiWithOptions edits/creates a wiki page from the queued object.
+//
+//	wiki, _ := sub.Wiki("index")
+//	err := sub.EditWikiWithOptions("index", mira.WikiEditOptions{
+//		Content:  wiki.ContentMD + "\n\nmore text",
+//		Previous: wiki.RevisionID,
+//	})
+//	var conflict *mira.WikiConflictError
+//	if errors.As(err, &conflict) {
+//		// someone else edited the page, conflict.NewContent is the current content
+//	}
+//
+// Valid objects: Subreddit
+func (q Queued) EditWikiWithOptions(page string, opts WikiEditOptions) error {
+	return q.EditWikiWithOptionsContext(context.Background(), page, opts)
+}
+
+// EditWikiWithOptionsContext is like EditWikiWithOptions, but with a context.
+func (q Queued) EditWikiWithOptionsContext(ctx context.Context, page string, opts WikiEditOptions) error {
+	if q.kind != models.KSubreddit {
+		return fmt.Errorf("'%s' type does not have an option for editwiki", q.kind)
+	}
+
+	params := map[string]string{
+		"content": opts.Content,
+		"page":    page,
+		"reason":  opts.Reason,
+	}
+	if opts.Previous != "" {
+		params["previous"] = opts.Previous
+	}
+	target := q.endpoints.OAuth + "/r/" + q.name + "/api/wiki/edit"
+	_, err := q.MiraRequestContext(ctx, "POST", target, params)
+
+	// API returns {}
+
+	return err
+}
+
+// WikiRevisions returns up to limit revisions of a wiki page of the queued object, newest first.
+// If page is empty, the revisions of all pages are returned.
+// Valid objects: Subreddit
+func (q Queued) WikiRevisions(page string, limit int) ([]*models.WikiRevision, error) {
+	return q.WikiRevisionsContext(context.Background(), page, limit)
+}
+
+// WikiRevisionsContext is like WikiRevisions, but with a context.
+func (q Queued) WikiRevisionsContext(ctx context.Context, page string, limit int) ([]*models.WikiRevision, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki revisions", q.kind)
+	}
+	ret, _, err := q.getWikiRevisions(ctx, q.name, wikiRevisionsTarget(q.Reddit, q.name, page), map[string]string{
+		"limit": strconv.Itoa(limit),
+	})
+	return ret, err
+}
+
+// wikiRevisionsTarget returns the URL of the revisions of a page, or of all pages if page is empty.
+func wikiRevisionsTarget(c *Reddit, sr, page string) string {
+	target := c.endpoints.OAuth + "/r/" + sr + "/wiki/revisions"
+	if page != "" {
+		target += "/" + page
+	}
+	return target
+}
+
+// getWikiRevisions requests a page of wiki revisions & returns the cursor of the next page ("" at the end).
+// reddit sends them in a Listing, but without the usual kind & data of each item.
+func (c *Reddit) getWikiRevisions(ctx context.Context, sr, target string, params map[string]string) ([]*models.WikiRevision, string, error) {
+	ans, err := c.MiraRequestContext(ctx, "GET", target, params)
+	if err != nil {
+		return nil, "", err
+	}
+	var list struct {
+		Data struct {
+			Children []*models.WikiRevision `json:"children"`
+			After    string                 `json:"after"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(ans, &list); err != nil {
+		return nil, "", err
+	}
+	for _, rev := range list.Data.Children {
+		rev.Subreddit = sr
+	}
+	return list.Data.Children, list.Data.After, nil
+}
+
+// RevertWiki reverts a wiki page of the queued object to the given revision.
+// Valid objects: Subreddit
+func (q Queued) RevertWiki(page, revisionID string) error {
+	return q.RevertWikiContext(context.Background(), page, revisionID)
+}
+
+// RevertWikiContext is like RevertWiki, but with a context.
+func (q Queued) RevertWikiContext(ctx context.Context, page, revisionID string) error {
+	if q.kind != models.KSubreddit {
+		return fmt.Errorf("'%s' type does not have an option to revert wiki", q.kind)
+	}
+
+	target := q.endpoints.OAuth + "/r/" + q.name + "/api/wiki/revert"
+	_, err := q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"page":     page,
+		"revision": revisionID,
+	})
+	return err
+}
+
+// ToggleWikiRevisionHidden hides a revision of a wiki page of the queued object from the public
+// history, or shows it again if it was hidden. Returns true if the revision is hidden now.
+// Valid objects: Subreddit
+func (q Queued) ToggleWikiRevisionHidden(page, revisionID string) (bool, error) {
+	return q.ToggleWikiRevisionHiddenContext(context.Background(), page, revisionID)
+}
+
+// ToggleWikiRevisionHiddenContext is like ToggleWikiRevisionHidden, but with a context.
+func (q Queued) ToggleWikiRevisionHiddenContext(ctx context.Context, page, revisionID string) (bool, error) {
+	if q.kind != models.KSubreddit {
+		return false, fmt.Errorf("'%s' type does not have an option to hide wiki revisions", q.kind)
+	}
+
+	target := q.endpoints.OAuth + "/r/" + q.name + "/api/wiki/hide"
+	ans, err := q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"page":     page,
+		"revision": revisionID,
+	})
+	if err != nil {
+		return false, err
+	}
+	var ret struct {
+		Status bool `json:"status"`
+	}
+	if err := json.Unmarshal(ans, &ret); err != nil {
+		return false, err
+	}
+	return ret.Status, nil
+}
+
+// WikiSettings returns who may edit a wiki page of the queued object.
+// Valid objects: Subreddit
+func (q Queued) WikiSettings(page string) (*models.WikiSettings, error) {
+	return q.WikiSettingsContext(context.Background(), page)
+}
+
+// WikiSettingsContext is like WikiSettings, but with a context.
+func (q Queued) WikiSettingsContext(ctx context.Context, page string) (*models.WikiSettings, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki settings", q.kind)
+	}
+	return q.wikiSettings(ctx, "GET", q.name, page, nil)
+}
+
+// SetWikiSettings changes who may edit a wiki page of the queued object, and if it is
+// listed in the list of pages. The new settings are returned.
+// Valid objects: Subreddit
+func (q Queued) SetWikiSettings(page string, permLevel models.WikiPermLevel, listed bool) (*models.WikiSettings, error) {
+	return q.SetWikiSettingsContext(context.Background(), page, permLevel, listed)
+}
+
+// SetWikiSettingsContext is like SetWikiSettings, but with a context.
+func (q Queued) SetWikiSettingsContext(ctx context.Context, page string, permLevel models.WikiPermLevel, listed bool) (*models.WikiSettings, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option for wiki settings", q.kind)
+	}
+	return q.wikiSettings(ctx, "POST", q.name, page, map[string]string{
+		"permlevel": strconv.Itoa(int(permLevel)),
+		"listed":    strconv.FormatBool(listed),
+	})
+}
+
+func (c *Reddit) wikiSettings(ctx context.Context, method, sr, page string, params map[string]string) (*models.WikiSettings, error) {
+	target := c.endpoints.OAuth + "/r/" + sr + "/wiki/settings/" + page
+	ans, err := c.MiraRequestContext(ctx, method, target, params)
+	if err != nil {
+		return nil, err
+	}
+	ret := &models.Response{}
+	if err := json.Unmarshal(ans, ret); err != nil {
+		return nil, err
+	}
+
+	settings, ok := ret.Data.(*models.WikiSettings)
+	if !ok {
+		return nil, fmt.Errorf("couldn't convert to WikiSettings struct")
+	}
+
+	return settings, nil
+}
+
+// AddWikiEditor allows a redditor to edit a wiki page of the queued object, regardless of its permission level.
+// Valid objects: Subreddit
+func (q Queued) AddWikiEditor(page, redditor string) error {
+	return q.AddWikiEditorContext(context.Background(), page, redditor)
+}
+
+// AddWikiEditorContext is like AddWikiEditor, but with a context.
+func (q Queued) AddWikiEditorContext(ctx context.Context, page, redditor string) error {
+	return q.wikiEditor(ctx, "add", page, redditor)
+}
+
+// RemoveWikiEditor removes a redditor from the editors of a wiki page of the queued object.
+// Valid objects: Subreddit
+func (q Queued) RemoveWikiEditor(page, redditor string) error {
+	return q.RemoveWikiEditorContext(context.Background(), page, redditor)
+}
+
+// RemoveWikiEditorContext is like RemoveWikiEditor, but with a context.
+func (q Queued) RemoveWikiEditorContext(ctx context.Context, page, redditor string) error {
+	return q.wikiEditor(ctx, "del", page, redditor)
+}
+
+func (q Queued) wikiEditor(ctx context.Context, act, page, redditor string) error {
+	if q.kind != models.KSubreddit {
+		return fmt.Errorf("'%s' type does not have an option for wiki editors", q.kind)
+	}
+
+	target := q.endpoints.OAuth + "/r/" + q.name + "/api/wiki/alloweditor/" + act
+	_, err := q.MiraRequestContext(ctx, "POST", target, map[string]string{
+		"page":     page,
+		"username": redditor,
+	})
+	return err
+}
@@ -0,0 +1,100 @@
+package mira
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy defines how requests failing with a transient error (5xx responses, 429 Too Many Requests,
+// connection resets & timeouts) are retried. It is set per Reddit instance via Values.Retry.
+//
+// GET requests are retried by default. Since other requests (i.e. submitting a post) may not be
+// idempotent, they are only retried if RetryPOST is set.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is tried, including the first attempt.
+	// Values <= 1 disable retries.
+	MaxAttempts int
+	// MinBackoff is the time to wait before the first retry. It is doubled with each
+	// further attempt, up to MaxBackoff. A random jitter of up to 50% is subtracted.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// RetryPOST enables retries for requests that are not GET requests.
+	RetryPOST bool
+}
+
+// DefaultRetryPolicy is the RetryPolicy set by SetDefault.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+// backoff returns how long to wait after the given (failed) attempt.
+// If reddit told us how long to wait (Retry-After), that value is used instead.
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	var rlErr *RateLimitError
+	if errors.As(err, &rlErr) && rlErr.RetryAfter > 0 {
+		return rlErr.RetryAfter
+	}
+	var srvErr *ServerError
+	if errors.As(err, &srvErr) && srvErr.RetryAfter > 0 {
+		return srvErr.RetryAfter
+	}
+
+	d := p.MinBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d - time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// isTransient tells you if a request failing with err is worth retrying.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rlErr *RateLimitError
+	if errors.As(err, &rlErr) {
+		return true
+	}
+	var srvErr *ServerError
+	if errors.As(err, &srvErr) {
+		switch srvErr.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package mira_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+)
+
+func TestRetry(t *testing.T) {
+	const path = "/r/test/new.json"
+	tests := []struct {
+		name     string
+		fails    int
+		status   int
+		attempts int
+		wantErr  interface{}
+	}{
+		{name: "recovers", fails: 2, status: http.StatusServiceUnavailable, attempts: 3},
+		{name: "gives up", fails: 10, status: http.StatusBadGateway, attempts: 4, wantErr: new(*mira.ServerError)},
+		{name: "not transient", fails: 10, status: http.StatusForbidden, attempts: 1, wantErr: new(*mira.ForbiddenError)},
+		{name: "not implemented", fails: 10, status: http.StatusNotImplemented, attempts: 1, wantErr: new(*mira.ServerError)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := miratest.NewServer()
+			defer srv.Close()
+			reddit, err := srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv.Fail(path, tc.fails, tc.status, "")
+
+			_, err = reddit.Subreddit("test").Posts("new", "all", 10)
+			if tc.wantErr == nil && err != nil {
+				t.Errorf("got error %v, want none", err)
+			} else if tc.wantErr != nil && !errors.As(err, tc.wantErr) {
+				t.Errorf("got error %T (%v), want %T", err, err, tc.wantErr)
+			}
+			if n := len(srv.RequestsTo(path)); n != tc.attempts {
+				t.Errorf("got %d attempts, want %d", n, tc.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryPOST(t *testing.T) {
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Fail("/api/submit", 1, http.StatusServiceUnavailable, "")
+	if _, err := reddit.Subreddit("test").Submit("title", "text"); err == nil {
+		t.Error("Submit succeeded, want error without RetryPOST")
+	}
+	if n := len(srv.ActionsTo("/api/submit")); n != 1 {
+		t.Errorf("got %d attempts without RetryPOST, want 1", n)
+	}
+
+	reddit.Values.Retry.RetryPOST = true
+	srv.Fail("/api/submit", 1, http.StatusServiceUnavailable, "")
+	if _, err := reddit.Subreddit("test").Submit("title", "text"); err != nil {
+		t.Errorf("Submit with RetryPOST failed: %v", err)
+	}
+	if n := len(srv.ActionsTo("/api/submit")); n != 3 {
+		t.Errorf("got %d attempts in total, want 3", n)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	const path = "/r/test/new.json"
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			srv := miratest.NewServer()
+			defer srv.Close()
+			reddit, err := srv.Reddit()
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv.FailRetryAfter(path, 1, status, 200*time.Millisecond)
+
+			if _, err := reddit.Subreddit("test").Posts("new", "all", 10); err != nil {
+				t.Fatal(err)
+			}
+			reqs := srv.RequestsTo(path)
+			if len(reqs) != 2 {
+				t.Fatalf("got %d attempts, want 2", len(reqs))
+			}
+			// the backoff of srv.Reddit() is at most 10ms
+			if d := reqs[1].Time.Sub(reqs[0].Time); d < 200*time.Millisecond {
+				t.Errorf("retried after %s, want Retry-After of 200ms", d)
+			}
+		})
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	const path = "/r/test/new.json"
+	srv := miratest.NewServer()
+	defer srv.Close()
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reddit.Values.Retry.MinBackoff = 10 * time.Second
+	reddit.Values.Retry.MaxBackoff = 10 * time.Second
+	srv.Fail(path, 1, http.StatusServiceUnavailable, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = reddit.Subreddit("test").PostsContext(ctx, "new", "all", 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("returned after %s, want right after the deadline", d)
+	}
+	if n := len(srv.RequestsTo(path)); n != 1 {
+		t.Errorf("got %d attempts, want 1", n)
+	}
+}
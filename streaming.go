@@ -1,146 +1,667 @@
 package mira
 
 import (
-	"container/ring"
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ttgmpsn/mira/models"
 )
 
-// SubmissionStream has two objects: a channel "C" where you can receive
-// Submissions (posts or comments), and a channel "close" - close that
+// SubmissionStream has three objects: a channel "C" where you can receive
+// Submissions (posts or comments), a channel "Errors" where errors that
+// happen while polling are sent, and a channel "Close" - close that
 // channel to stop receiving events. Please use the close channel appropriately
 // or you'll be polling reddit non-stop!
+//
+// Temporary errors are retried in the next poll, other errors end the stream.
+// C & Errors are closed once the stream has stopped. You don't have to receive
+// from Errors, but if you don't, you won't know why a stream ended.
 type SubmissionStream struct {
-	C     <-chan models.Submission
-	Close chan struct{}
+	C      <-chan models.Submission
+	Errors <-chan error
+	Close  chan struct{}
 }
 
-// StreamComments streams comments for the last queued object.
-// Valid objects: Subreddit, (Redditor)
-func (c *Reddit) StreamComments() (*SubmissionStream, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+// StreamComments streams comments for the queued object.
+// Valid objects: Subreddit, Redditor
+func (q Queued) StreamComments() (*SubmissionStream, error) {
+	return q.StreamCommentsContext(context.Background())
+}
+
+// StreamCommentsContext is like StreamComments, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamCommentsContext(ctx context.Context) (*SubmissionStream, error) {
+	switch q.kind {
 	case models.KSubreddit:
-		return c.streamSubredditComments(name)
-	/*case models.KRedditor:
-	return c.streamRedditorComments(name)*/
+		return q.streamSubredditComments(ctx, q.name, q.streamOpts)
+	case models.KRedditor:
+		return q.streamRedditorComments(ctx, strings.Split(q.name, "+"), q.streamOpts)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option to stream comments", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option to stream comments", q.kind)
 	}
 }
 
-// StreamPosts streams posts for the last queued object.
-// Valid objects: Subreddit, (Redditor)
-func (c *Reddit) StreamPosts() (*SubmissionStream, error) {
-	name, ttype := c.getQueue()
-	switch ttype {
+// StreamPosts streams posts for the queued object.
+// Valid objects: Subreddit, Redditor
+func (q Queued) StreamPosts() (*SubmissionStream, error) {
+	return q.StreamPostsContext(context.Background())
+}
+
+// StreamPostsContext is like StreamPosts, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamPostsContext(ctx context.Context) (*SubmissionStream, error) {
+	switch q.kind {
 	case models.KSubreddit:
-		return c.streamSubredditPosts(name)
-	/*case models.KRedditor:
-	return c.streamRedditorComments(name)*/
+		return q.streamSubredditPosts(ctx, q.name, q.streamOpts)
+	case models.KRedditor:
+		return q.streamRedditorPosts(ctx, strings.Split(q.name, "+"), q.streamOpts)
 	default:
-		return nil, fmt.Errorf("'%s' type does not have an option to stream posts", ttype)
+		return nil, fmt.Errorf("'%s' type does not have an option to stream posts", q.kind)
+	}
+}
+
+// StreamModQueue streams items in the mod queue of the queued object.
+// Items are sent again if their number of reports changes.
+// Valid objects: Subreddit
+func (q Queued) StreamModQueue() (*SubmissionStream, error) {
+	return q.StreamModQueueContext(context.Background())
+}
+
+// StreamModQueueContext is like StreamModQueue, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamModQueueContext(ctx context.Context) (*SubmissionStream, error) {
+	return q.streamModListing(ctx, "modqueue")
+}
+
+// StreamReports streams reported items of the queued object.
+// Items are sent again if their number of reports changes.
+// Valid objects: Subreddit
+func (q Queued) StreamReports() (*SubmissionStream, error) {
+	return q.StreamReportsContext(context.Background())
+}
+
+// StreamReportsContext is like StreamReports, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamReportsContext(ctx context.Context) (*SubmissionStream, error) {
+	return q.streamModListing(ctx, "reports")
+}
+
+// StreamSpam streams items removed as spam of the queued object.
+// Items are sent again if their number of reports changes.
+// Valid objects: Subreddit
+func (q Queued) StreamSpam() (*SubmissionStream, error) {
+	return q.StreamSpamContext(context.Background())
+}
+
+// StreamSpamContext is like StreamSpam, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamSpamContext(ctx context.Context) (*SubmissionStream, error) {
+	return q.streamModListing(ctx, "spam")
+}
+
+// StreamEdited streams recently edited items of the queued object.
+// Items are sent again if they are edited again, or their number of reports changes.
+// Valid objects: Subreddit
+func (q Queued) StreamEdited() (*SubmissionStream, error) {
+	return q.StreamEditedContext(context.Background())
+}
+
+// StreamEditedContext is like StreamEdited, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamEditedContext(ctx context.Context) (*SubmissionStream, error) {
+	return q.streamModListing(ctx, "edited")
+}
+
+// StreamUnmoderated streams items no moderator has looked at yet of the queued object.
+// Items are sent again if their number of reports changes.
+// Valid objects: Subreddit
+func (q Queued) StreamUnmoderated() (*SubmissionStream, error) {
+	return q.StreamUnmoderatedContext(context.Background())
+}
+
+// StreamUnmoderatedContext is like StreamUnmoderated, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamUnmoderatedContext(ctx context.Context) (*SubmissionStream, error) {
+	return q.streamModListing(ctx, "unmoderated")
+}
+
+func (q Queued) streamModListing(ctx context.Context, where string) (*SubmissionStream, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option to stream %s", q.kind, where)
+	}
+	if _, err := q.getModListing(ctx, q.name, where, 1); err != nil {
+		return nil, err
+	}
+	key := reportsKey
+	if where == "edited" {
+		key = editedKey
 	}
+	opts := q.streamOpts.withDefaults(15 * time.Second)
+	return newSubmissionStream(ctx, newPoller(opts, key, func(ctx context.Context) ([]models.Submission, error) {
+		return q.getModListing(ctx, q.name, where, opts.PageSize)
+	})), nil
 }
 
-func (c *Reddit) streamSubredditComments(name string) (*SubmissionStream, error) {
-	sendC := make(chan models.Submission, 100)
-	s := &SubmissionStream{
-		C:     sendC,
-		Close: make(chan struct{}),
+func (c *Reddit) streamSubredditComments(ctx context.Context, name string, opts StreamOptions) (*SubmissionStream, error) {
+	_, err := c.Subreddit(name).PostsContext(ctx, "new", "all", 1)
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults(45 * time.Second)
+	var last models.RedditID
+	p := newPoller(opts, submissionKey, func(ctx context.Context) ([]models.Submission, error) {
+		comments, err := c.getSubredditCommentsAfter(ctx, name, "new", last, opts.PageSize)
+		if err == nil && len(comments) == 0 {
+			last = ""
+		} else if len(comments) > 2 {
+			last = comments[1].GetID()
+		}
+		ret := make([]models.Submission, 0, len(comments))
+		for _, comment := range comments {
+			ret = append(ret, comment)
+		}
+		return ret, err
+	})
+	if _, err := p.resume(ctx, "comments/r/"+name, func(ctx context.Context, cp Checkpoint) ([]models.Submission, error) {
+		return backfill[models.Submission](ctx, c, c.endpoints.OAuth+"/r/"+name+"/comments.json", map[string]string{"sort": "new"}, cp, opts.Backfill)
+	}); err != nil {
+		return nil, err
 	}
-	_, err := c.Subreddit(name).Posts("new", "all", 1)
+	return newSubmissionStream(ctx, p), nil
+}
+
+func (c *Reddit) streamSubredditPosts(ctx context.Context, name string, opts StreamOptions) (*SubmissionStream, error) {
+	_, err := c.Subreddit(name).PostsContext(ctx, "new", "all", 1)
 	if err != nil {
 		return nil, err
 	}
+	opts = opts.withDefaults(5 * time.Second)
 	var last models.RedditID
-	go func() {
-		sent := ring.New(100)
-		for {
-			select {
-			case <-s.Close:
-				return
-			default:
+	p := newPoller(opts, submissionKey, func(ctx context.Context) ([]models.Submission, error) {
+		posts, err := c.getSubredditPostsAfter(ctx, name, last, opts.PageSize)
+		if err == nil && len(posts) == 0 {
+			last = ""
+		} else if len(posts) > 2 {
+			last = posts[1].GetID()
+		}
+		ret := make([]models.Submission, 0, len(posts))
+		for _, post := range posts {
+			ret = append(ret, post)
+		}
+		return ret, err
+	})
+	if _, err := p.resume(ctx, "posts/r/"+name, func(ctx context.Context, cp Checkpoint) ([]models.Submission, error) {
+		return backfill[models.Submission](ctx, c, c.endpoints.OAuth+"/r/"+name+"/new.json", nil, cp, opts.Backfill)
+	}); err != nil {
+		return nil, err
+	}
+	return newSubmissionStream(ctx, p), nil
+}
+
+func (c *Reddit) streamRedditorComments(ctx context.Context, names []string, opts StreamOptions) (*SubmissionStream, error) {
+	for _, name := range names {
+		if _, err := c.getRedditorComments(ctx, name, "new", "all", 1); err != nil {
+			return nil, err
+		}
+	}
+	opts = opts.withDefaults(45 * time.Second)
+	p := newPoller(opts, submissionKey, func(ctx context.Context) ([]models.Submission, error) {
+		var ret []models.Submission
+		for _, name := range names {
+			comments, err := c.getRedditorComments(ctx, name, "new", "all", opts.PageSize)
+			if err != nil {
+				return ret, err
 			}
-			comments, err := c.Subreddit(name).CommentsAfter("new", last, 100)
+			for _, comment := range comments {
+				ret = append(ret, comment)
+			}
+		}
+		sortNewestFirst(ret)
+		return ret, nil
+	})
+	if _, err := p.resume(ctx, "comments/u/"+strings.Join(names, "+"), func(ctx context.Context, cp Checkpoint) ([]models.Submission, error) {
+		var ret []models.Submission
+		for _, name := range names {
+			subs, err := backfill[models.Submission](ctx, c, c.endpoints.OAuth+"/u/"+name+"/comments.json", map[string]string{"sort": "new"}, cp, opts.Backfill)
 			if err != nil {
-				close(sendC)
-				return
+				return nil, err
 			}
-			for i := len(comments) - 1; i >= 0; i-- {
-				if ringContains(sent, comments[i].GetID()) {
-					continue
-				}
-				sendC <- comments[i]
-				sent.Value = comments[i].GetID()
-				sent = sent.Next()
+			ret = append(ret, subs...)
+		}
+		sortNewestFirst(ret)
+		return ret, nil
+	}); err != nil {
+		return nil, err
+	}
+	return newSubmissionStream(ctx, p), nil
+}
+
+func (c *Reddit) streamRedditorPosts(ctx context.Context, names []string, opts StreamOptions) (*SubmissionStream, error) {
+	for _, name := range names {
+		if _, err := c.getRedditorPosts(ctx, name, "new", "all", 1); err != nil {
+			return nil, err
+		}
+	}
+	opts = opts.withDefaults(30 * time.Second)
+	p := newPoller(opts, submissionKey, func(ctx context.Context) ([]models.Submission, error) {
+		var ret []models.Submission
+		for _, name := range names {
+			posts, err := c.getRedditorPosts(ctx, name, "new", "all", opts.PageSize)
+			if err != nil {
+				return ret, err
+			}
+			for _, post := range posts {
+				ret = append(ret, post)
 			}
-			if len(comments) == 0 {
-				last = ""
-			} else if len(comments) > 2 {
-				last = comments[1].GetID()
+		}
+		sortNewestFirst(ret)
+		return ret, nil
+	})
+	if _, err := p.resume(ctx, "posts/u/"+strings.Join(names, "+"), func(ctx context.Context, cp Checkpoint) ([]models.Submission, error) {
+		var ret []models.Submission
+		for _, name := range names {
+			subs, err := backfill[models.Submission](ctx, c, c.endpoints.OAuth+"/u/"+name+"/submitted/new.json", nil, cp, opts.Backfill)
+			if err != nil {
+				return nil, err
 			}
-			time.Sleep(45 * time.Second)
+			ret = append(ret, subs...)
 		}
-	}()
-	return s, nil
+		sortNewestFirst(ret)
+		return ret, nil
+	}); err != nil {
+		return nil, err
+	}
+	return newSubmissionStream(ctx, p), nil
+}
+
+// newSubmissionStream starts p and returns its stream.
+func newSubmissionStream(ctx context.Context, p *poller[models.Submission]) *SubmissionStream {
+	c, errs, stop := p.start(ctx)
+	return &SubmissionStream{C: c, Errors: errs, Close: stop}
 }
 
-func (c *Reddit) streamSubredditPosts(name string) (*SubmissionStream, error) {
-	sendC := make(chan models.Submission, 100)
-	s := &SubmissionStream{
-		C:     sendC,
-		Close: make(chan struct{}),
+// submissionKey identifies a submission by its ID.
+func submissionKey(sub models.Submission) string { return string(sub.GetID()) }
+
+// reportsKey identifies a submission by its ID & number of reports.
+func reportsKey(sub models.Submission) string {
+	return fmt.Sprintf("%s:%d", sub.GetID(), sub.GetReports().Num)
+}
+
+// editedKey identifies a submission by its ID, number of reports & time of the last edit.
+func editedKey(sub models.Submission) string {
+	return fmt.Sprintf("%s:%d", reportsKey(sub), sub.EditedAt().Unix())
+}
+
+// sortNewestFirst sorts submissions of multiple listings the way reddit sorts a single one.
+func sortNewestFirst(subs []models.Submission) {
+	sort.SliceStable(subs, func(i, j int) bool {
+		return subs[i].CreatedAt().After(subs[j].CreatedAt())
+	})
+}
+
+// MessageStream is like SubmissionStream, but for items in your inbox:
+// receive them from "C", errors from "Errors", and close "Close" to stop the stream.
+type MessageStream struct {
+	C      <-chan *models.Message
+	Errors <-chan error
+	Close  chan struct{}
+}
+
+// InboxStreamOptions configures an inbox stream.
+type InboxStreamOptions struct {
+	// Where is the inbox folder to stream, i.e. InboxMentions. Defaults to InboxAll.
+	Where string
+	// MarkRead marks messages as read once they have been delivered.
+	MarkRead bool
+}
+
+// StreamInbox streams everything arriving in the inbox of the queued object:
+// private messages, comment replies, post replies & mentions.
+// Valid objects: Me
+func (q Queued) StreamInbox() (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(context.Background(), InboxStreamOptions{})
+}
+
+// StreamInboxContext is like StreamInbox, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamInboxContext(ctx context.Context) (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(ctx, InboxStreamOptions{})
+}
+
+// StreamMentions streams username mentions of the queued object.
+// Valid objects: Me
+func (q Queued) StreamMentions() (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(context.Background(), InboxStreamOptions{Where: InboxMentions})
+}
+
+// StreamMentionsContext is like StreamMentions, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamMentionsContext(ctx context.Context) (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(ctx, InboxStreamOptions{Where: InboxMentions})
+}
+
+// StreamUnread streams unread items in the inbox of the queued object.
+// Valid objects: Me
+func (q Queued) StreamUnread() (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(context.Background(), InboxStreamOptions{Where: InboxUnread})
+}
+
+// StreamUnreadContext is like StreamUnread, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamUnreadContext(ctx context.Context) (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(ctx, InboxStreamOptions{Where: InboxUnread})
+}
+
+// StreamInboxWithOptions streams an inbox folder of the queued object.
+//
+// Items that were already read when the stream started are skipped, everything unread is
+// delivered. Together with MarkRead, a restarted bot doesn't receive the same item twice.
+// If the stream resumes from a checkpoint (see StreamOptions), everything that arrived after
+// the checkpoint is delivered instead.
+// Valid objects: Me
+func (q Queued) StreamInboxWithOptions(opts InboxStreamOptions) (*MessageStream, error) {
+	return q.StreamInboxWithOptionsContext(context.Background(), opts)
+}
+
+// StreamInboxWithOptionsContext is like StreamInboxWithOptions, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamInboxWithOptionsContext(ctx context.Context, opts InboxStreamOptions) (*MessageStream, error) {
+	if _, _, err := q.checkType("me"); err != nil {
+		return nil, err
+	}
+	if opts.Where == "" {
+		opts.Where = InboxAll
+	}
+	return q.streamInbox(ctx, opts, q.streamOpts)
+}
+
+func (c *Reddit) streamInbox(ctx context.Context, opts InboxStreamOptions, sopts StreamOptions) (*MessageStream, error) {
+	sopts = sopts.withDefaults(15 * time.Second)
+	params := map[string]string{
+		"limit": strconv.Itoa(sopts.PageSize),
+		"mark":  "false",
 	}
-	_, err := c.Subreddit(name).Posts("new", "all", 1)
+	existing, err := c.getMessages(ctx, opts.Where, params)
 	if err != nil {
 		return nil, err
 	}
-	var last models.RedditID
-	go func() {
-		sent := ring.New(100)
-		for {
-			select {
-			case <-s.Close:
-				return
-			default:
+	p := newPoller(sopts, func(m *models.Message) string { return string(m.GetID()) }, func(ctx context.Context) ([]*models.Message, error) {
+		return c.getMessages(ctx, opts.Where, params)
+	})
+	resumed, err := p.resume(ctx, "inbox/"+opts.Where, func(ctx context.Context, cp Checkpoint) ([]*models.Message, error) {
+		return backfill[*models.Message](ctx, c, c.endpoints.OAuth+"/message/"+opts.Where, map[string]string{"mark": "false"}, cp, sopts.Backfill)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(existing) - 1; i >= 0 && !resumed; i-- {
+		// read messages have been handled before, we only deliver unread ones
+		if !existing[i].New {
+			p.markSent(existing[i])
+		}
+	}
+	if opts.MarkRead {
+		p.delivered = func(ctx context.Context, messages []*models.Message) {
+			var read []string
+			for _, m := range messages {
+				if m.New {
+					read = append(read, string(m.GetID()))
+				}
 			}
-			posts, err := c.Subreddit(name).PostsAfter(last, 100)
-			if err != nil {
-				close(sendC)
+			if len(read) == 0 {
 				return
 			}
-			for i := len(posts) - 1; i >= 0; i-- {
-				if ringContains(sent, posts[i].GetID()) {
-					continue
-				}
-				sendC <- posts[i]
-				sent.Value = posts[i].GetID()
-				sent = sent.Next()
+			// if this fails, the messages are delivered again after a restart
+			_, _ = c.MiraRequestContext(ctx, "POST", c.endpoints.OAuth+"/api/read_message", map[string]string{
+				"id": strings.Join(read, ","),
+			})
+		}
+	}
+	msgs, errs, stop := p.start(ctx)
+	return &MessageStream{C: msgs, Errors: errs, Close: stop}, nil
+}
+
+// ModActionStream is like SubmissionStream, but for entries of the mod log:
+// receive them from "C", errors from "Errors", and close "Close" to stop the stream.
+type ModActionStream struct {
+	C      <-chan *models.ModAction
+	Errors <-chan error
+	Close  chan struct{}
+}
+
+// ModLogStreamOptions filters a mod log stream. Empty filters match everything.
+type ModLogStreamOptions struct {
+	// Actions are the types of actions to stream, i.e. models.ModActionBanUser.
+	Actions []models.ModActionType
+	// Mods are the names of the moderators whose actions are streamed.
+	Mods []string
+}
+
+// StreamModLog streams entries added to the mod log of the queued object after the stream started,
+// or after the checkpoint if the stream resumes from one (see StreamOptions).
+// Valid objects: Subreddit
+func (q Queued) StreamModLog() (*ModActionStream, error) {
+	return q.StreamModLogWithOptionsContext(context.Background(), ModLogStreamOptions{})
+}
+
+// StreamModLogContext is like StreamModLog, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamModLogContext(ctx context.Context) (*ModActionStream, error) {
+	return q.StreamModLogWithOptionsContext(ctx, ModLogStreamOptions{})
+}
+
+// StreamModLogWithOptions streams new entries of the mod log of the queued object
+// that match the filters in opts.
+// Valid objects: Subreddit
+func (q Queued) StreamModLogWithOptions(opts ModLogStreamOptions) (*ModActionStream, error) {
+	return q.StreamModLogWithOptionsContext(context.Background(), opts)
+}
+
+// StreamModLogWithOptionsContext is like StreamModLogWithOptions, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamModLogWithOptionsContext(ctx context.Context, opts ModLogStreamOptions) (*ModActionStream, error) {
+	if q.kind != models.KSubreddit {
+		return nil, fmt.Errorf("'%s' type does not have an option to stream modlog", q.kind)
+	}
+	return q.streamModLog(ctx, q.name, opts, q.streamOpts)
+}
+
+func (c *Reddit) streamModLog(ctx context.Context, sr string, opts ModLogStreamOptions, sopts StreamOptions) (*ModActionStream, error) {
+	sopts = sopts.withDefaults(30 * time.Second)
+	params := map[string]string{
+		"limit": strconv.Itoa(sopts.PageSize),
+	}
+	if len(opts.Mods) > 0 {
+		params["mod"] = strings.Join(opts.Mods, ",")
+	}
+	// reddit can only filter by one type, more are filtered below
+	if len(opts.Actions) == 1 {
+		params["type"] = string(opts.Actions[0])
+	}
+	existing, err := c.getModLog(ctx, sr, params)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPoller(sopts, func(a *models.ModAction) string { return a.ID }, func(ctx context.Context) ([]*models.ModAction, error) {
+		actions, err := c.getModLog(ctx, sr, params)
+		ret := actions[:0]
+		for _, a := range actions {
+			if opts.matches(a) {
+				ret = append(ret, a)
 			}
-			if len(posts) == 0 {
-				last = ""
-			} else if len(posts) > 2 {
-				last = posts[1].GetID()
+		}
+		return ret, err
+	})
+	resumed, err := p.resume(ctx, "modlog/r/"+sr, func(ctx context.Context, cp Checkpoint) ([]*models.ModAction, error) {
+		actions, err := backfill[*models.ModAction](ctx, c, c.endpoints.OAuth+"/r/"+sr+"/about/log.json", params, cp, sopts.Backfill)
+		ret := actions[:0]
+		for _, a := range actions {
+			if opts.matches(a) {
+				ret = append(ret, a)
 			}
-			time.Sleep(5 * time.Second)
 		}
-	}()
-	return s, nil
+		return ret, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// without a checkpoint, only entries added after the stream started are sent
+	for i := 0; i < len(existing) && !resumed; i++ {
+		p.markSent(existing[i])
+	}
+	actions, errs, stop := p.start(ctx)
+	return &ModActionStream{C: actions, Errors: errs, Close: stop}, nil
+}
+
+// matches tells you if a passes the filters.
+func (opts ModLogStreamOptions) matches(a *models.ModAction) bool {
+	if len(opts.Actions) > 0 {
+		found := false
+		for _, t := range opts.Actions {
+			found = found || t == a.Action
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(opts.Mods) > 0 {
+		found := false
+		for _, mod := range opts.Mods {
+			found = found || strings.EqualFold(mod, a.Mod)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
-func ringContains(r *ring.Ring, n models.RedditID) bool {
-	ret := false
-	r.Do(func(p interface{}) {
-		if p == nil {
-			return
+// ModMailStream is like SubmissionStream, but for modmail: receive conversations with new
+// messages from "C", errors from "Errors", and close "Close" to stop the stream.
+type ModMailStream struct {
+	C      <-chan *ModMailUpdate
+	Errors <-chan error
+	Close  chan struct{}
+}
+
+// ModMailUpdate is a conversation sent by a modmail stream. It contains all messages of
+// the conversation, the ones the stream hasn't sent before are in NewMessages as well.
+type ModMailUpdate struct {
+	*models.NewModmailConversation
+	// NewMessages are the messages that arrived since the conversation was last sent, oldest first.
+	NewMessages []models.ModmailMessage
+	// NewConversation is set if the conversation started after the stream.
+	NewConversation bool
+}
+
+// IsNew tells you if a message of the conversation is in NewMessages.
+func (u *ModMailUpdate) IsNew(messageID string) bool {
+	for _, msg := range u.NewMessages {
+		if msg.ID == messageID {
+			return true
 		}
-		i := p.(models.RedditID)
-		if i == n {
-			ret = true
+	}
+	return false
+}
+
+// StreamModMail streams the modmail of the queued object: new conversations & new messages
+// in existing ones, including replies & private notes of moderators. Conversations are sent
+// again whenever they get new messages, updates without new messages (i.e. archiving) are
+// skipped. Messages that existed when the stream started are not sent.
+// Valid objects: Subreddit (multiple subreddits are allowed), Me (all subreddits you moderate)
+func (q Queued) StreamModMail() (*ModMailStream, error) {
+	return q.StreamModMailContext(context.Background())
+}
+
+// StreamModMailContext is like StreamModMail, but with a context.
+// The stream stops once ctx is cancelled.
+func (q Queued) StreamModMailContext(ctx context.Context) (*ModMailStream, error) {
+	params, err := q.modMailParams(models.ModmailAll)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' type does not have an option to stream modmail", q.kind)
+	}
+	return q.streamModMail(ctx, params, q.streamOpts)
+}
+
+func (c *Reddit) streamModMail(ctx context.Context, params map[string]string, sopts StreamOptions) (*ModMailStream, error) {
+	sopts = sopts.withDefaults(30 * time.Second)
+	params["limit"] = strconv.Itoa(sopts.PageSize)
+	target := c.endpoints.OAuth + "/api/mod/conversations"
+	existing, _, err := c.getModMail(ctx, target, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// conversations & messages sent before. Conversations have many messages, so more of them are remembered.
+	convs := newSeenSet(sopts.Remember)
+	messages := newSeenSet(sopts.Remember * 10)
+	var p *poller[*ModMailUpdate]
+	p = newPoller(sopts, modMailKey, func(ctx context.Context) ([]*ModMailUpdate, error) {
+		list, _, err := c.getModMail(ctx, target, params)
+		var ret []*ModMailUpdate
+		for _, conv := range list {
+			// the list contains the IDs of all messages, so we only request conversations with new ones
+			u := &ModMailUpdate{NewModmailConversation: conv}
+			if p.seen.has(modMailKey(u)) {
+				continue
+			}
+			if !hasNewModMail(conv, messages) {
+				p.markSent(u)
+				continue
+			}
+			full, err := c.GetModMailByIDContext(ctx, conv.Conversation.ID, false)
+			if err != nil {
+				return ret, err
+			}
+			u.NewModmailConversation = full
+			for _, msg := range full.SortedMessages() {
+				if !messages.has(msg.ID) {
+					u.NewMessages = append(u.NewMessages, msg)
+				}
+			}
+			u.NewConversation = !convs.has(conv.Conversation.ID)
+			ret = append(ret, u)
 		}
+		return ret, err
 	})
-	return ret
+	p.delivered = func(ctx context.Context, updates []*ModMailUpdate) {
+		for _, u := range updates {
+			convs.add(u.Conversation.ID)
+			for _, msg := range u.NewMessages {
+				messages.add(msg.ID)
+			}
+		}
+	}
+	for i := len(existing) - 1; i >= 0; i-- {
+		p.markSent(&ModMailUpdate{NewModmailConversation: existing[i]})
+		convs.add(existing[i].Conversation.ID)
+		for _, obj := range existing[i].Conversation.ObjIDs {
+			if obj.Key == "messages" {
+				messages.add(obj.ID)
+			}
+		}
+	}
+	updates, errs, stop := p.start(ctx)
+	return &ModMailStream{C: updates, Errors: errs, Close: stop}, nil
+}
+
+// modMailKey identifies a conversation by its ID & time of the last update.
+func modMailKey(u *ModMailUpdate) string {
+	return u.Conversation.ID + "/" + u.CreatedAt().Format(time.RFC3339Nano)
+}
+
+// hasNewModMail tells you if a conversation contains messages that aren't in seen.
+func hasNewModMail(conv *models.NewModmailConversation, seen *seenSet) bool {
+	for _, obj := range conv.Conversation.ObjIDs {
+		if obj.Key == "messages" && !seen.has(obj.ID) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,365 @@
+package mira_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/miratest"
+	"github.com/ttgmpsn/mira/models"
+)
+
+// fastStream polls every few milliseconds, so tests don't have to wait for new items.
+var fastStream = mira.StreamOptions{
+	Interval:    5 * time.Millisecond,
+	MinInterval: 5 * time.Millisecond,
+	MaxInterval: 5 * time.Millisecond,
+}
+
+// receive receives n items from c, failing the test if they don't arrive in time.
+func receive[T any](t *testing.T, c <-chan T, n int) []T {
+	t.Helper()
+	var ret []T
+	timeout := time.After(5 * time.Second)
+	for len(ret) < n {
+		select {
+		case item, ok := <-c:
+			if !ok {
+				t.Fatalf("stream closed after %d of %d items", len(ret), n)
+			}
+			ret = append(ret, item)
+		case <-timeout:
+			t.Fatalf("received %d of %d items", len(ret), n)
+		}
+	}
+	return ret
+}
+
+// receiveNone fails the test if c delivers anything during the next polls.
+func receiveNone[T any](t *testing.T, c <-chan T) {
+	t.Helper()
+	select {
+	case item, ok := <-c:
+		if thing, isThing := any(item).(models.RedditThing); ok && isThing {
+			t.Fatalf("received unexpected item %s", thing.GetID())
+		} else if ok {
+			t.Fatalf("received unexpected item %+v", item)
+		}
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// stopStream closes a stream & waits until it has stopped. Errors sent by the stream fail the test.
+func stopStream[T any](t *testing.T, stop chan struct{}, c <-chan T, errs <-chan error) {
+	t.Helper()
+	close(stop)
+	for range c {
+	}
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func newTestServer(t *testing.T) (*miratest.Server, *mira.Reddit) {
+	t.Helper()
+	srv := miratest.NewServer()
+	t.Cleanup(srv.Close)
+	reddit, err := srv.Reddit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, reddit
+}
+
+func TestStreamInbox(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.AddMessage(&models.Message{Author: "alice", Subject: "handled before", Body: "hi"})
+	unread := srv.AddMessage(&models.Message{Author: "bob", Subject: "unread", Body: "hi", New: true})
+
+	me := reddit.Me().WithStreamOptions(fastStream)
+	stream, err := me.StreamInboxWithOptions(mira.InboxStreamOptions{MarkRead: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, stream.C, 1); got[0].Name != unread.Name {
+		t.Errorf("got %s, want the unread message %s", got[0].Name, unread.Name)
+	}
+	mention := srv.AddMessage(&models.Message{
+		Author:     "carol",
+		Body:       "/u/miratest",
+		WasComment: true,
+		Type:       models.MessageTypeUsernameMention,
+		New:        true,
+	})
+	if got := receive(t, stream.C, 1); got[0].Name != mention.Name || got[0].Type != models.MessageTypeUsernameMention {
+		t.Errorf("got %s, want the mention %s", got[0].Name, mention.Name)
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+
+	for _, m := range srv.Messages() {
+		if m.New {
+			t.Errorf("%s hasn't been marked as read", m.Name)
+		}
+	}
+	if n := len(srv.ActionsTo("/api/read_message")); n != 2 {
+		t.Errorf("marked messages as read %d times, want 2", n)
+	}
+
+	// everything has been read, a restarted stream doesn't deliver it again
+	stream, err = me.StreamInboxWithOptions(mira.InboxStreamOptions{MarkRead: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
+
+func TestStreamMentions(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	stream, err := reddit.Me().WithStreamOptions(fastStream).StreamMentions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddMessage(&models.Message{Author: "alice", Subject: "hi", Body: "a message", New: true})
+	mention := srv.AddMessage(&models.Message{
+		Author:     "bob",
+		Body:       "/u/miratest",
+		WasComment: true,
+		Type:       models.MessageTypeUsernameMention,
+		New:        true,
+	})
+	if got := receive(t, stream.C, 1); got[0].Name != mention.Name {
+		t.Errorf("got %s, want the mention %s", got[0].Name, mention.Name)
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+	if n := len(srv.ActionsTo("/api/read_message")); n != 0 {
+		t.Errorf("marked messages as read %d times without MarkRead", n)
+	}
+}
+
+func TestStreamRedditor(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	created := float64(time.Now().Add(-time.Hour).Unix())
+	post := func(author string) *models.Post {
+		created++
+		return srv.AddPost(&models.Post{Subreddit: "test", Author: author, CreatedUTC: created})
+	}
+	alice, bob := post("alice"), post("bob")
+	post("carol")
+
+	posts, err := reddit.Redditor("alice", "bob").WithStreamOptions(fastStream).StreamPosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := receive(t, posts.C, 2)
+	if got[0].GetID() != alice.Name || got[1].GetID() != bob.Name {
+		t.Errorf("got %s & %s, want %s & %s, oldest first", got[0].GetID(), got[1].GetID(), alice.Name, bob.Name)
+	}
+	post("carol")
+	newer := post("bob")
+	if got := receive(t, posts.C, 1); got[0].GetID() != newer.Name {
+		t.Errorf("got %s, want %s", got[0].GetID(), newer.Name)
+	}
+	receiveNone(t, posts.C)
+	stopStream(t, posts.Close, posts.C, posts.Errors)
+
+	comments, err := reddit.Redditor("alice").WithStreamOptions(fastStream).StreamComments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveNone(t, comments.C)
+	srv.AddComment(&models.Comment{LinkID: bob.Name, Author: "bob", Body: "not from alice"})
+	comment := srv.AddComment(&models.Comment{LinkID: bob.Name, Author: "alice", Body: "hi bob"})
+	if got := receive(t, comments.C, 1); got[0].GetID() != comment.Name {
+		t.Errorf("got %s, want %s", got[0].GetID(), comment.Name)
+	}
+	receiveNone(t, comments.C)
+	stopStream(t, comments.Close, comments.C, comments.Errors)
+}
+
+func TestStreamReports(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	post := srv.AddPost(&models.Post{Subreddit: "test", Title: "reported"})
+	srv.AddPost(&models.Post{Subreddit: "test", Title: "fine"})
+	srv.Report(post.Name, "spam")
+
+	stream, err := reddit.Subreddit("test").WithStreamOptions(fastStream).StreamReports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, stream.C, 1); got[0].GetID() != post.Name || got[0].GetReports().Num != 1 {
+		t.Errorf("got %s with %d reports, want %s with 1", got[0].GetID(), got[0].GetReports().Num, post.Name)
+	}
+	receiveNone(t, stream.C)
+
+	// sent again with the new number of reports
+	srv.Report(post.Name, "rude")
+	if got := receive(t, stream.C, 1); got[0].GetID() != post.Name || got[0].GetReports().Num != 2 {
+		t.Errorf("got %s with %d reports, want %s with 2", got[0].GetID(), got[0].GetReports().Num, post.Name)
+	}
+	comment := srv.AddComment(&models.Comment{LinkID: post.Name, Body: "reported as well"})
+	srv.Report(comment.Name, "spam")
+	if got := receive(t, stream.C, 1); got[0].GetID() != comment.Name {
+		t.Errorf("got %s, want %s", got[0].GetID(), comment.Name)
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
+
+func TestStreamModQueue(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	post := srv.AddPost(&models.Post{Subreddit: "test", Title: "reported"})
+	srv.Report(post.Name, "spam")
+
+	stream, err := reddit.Subreddit("test").WithStreamOptions(fastStream).StreamModQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, stream.C, 1); got[0].GetID() != post.Name {
+		t.Errorf("got %s, want %s", got[0].GetID(), post.Name)
+	}
+	// approved items leave the queue & aren't sent again
+	if err := reddit.Post(string(post.Name)).Approve(); err != nil {
+		t.Fatal(err)
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
+
+func TestStreamEdited(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	edited := time.Now().Add(-time.Minute).Truncate(time.Second)
+	post := srv.AddPost(&models.Post{Subreddit: "test", Title: "edited", Edited: models.Edited{IsEdited: true, At: edited}})
+	srv.AddPost(&models.Post{Subreddit: "test", Title: "not edited"})
+
+	stream, err := reddit.Subreddit("test").WithStreamOptions(fastStream).StreamEdited()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, stream.C, 1); got[0].GetID() != post.Name || !got[0].EditedAt().Equal(edited) {
+		t.Errorf("got %s edited at %s, want %s edited at %s", got[0].GetID(), got[0].EditedAt(), post.Name, edited)
+	}
+	receiveNone(t, stream.C)
+
+	// edited again
+	again := *post
+	again.Edited.At = edited.Add(30 * time.Second)
+	srv.AddPost(&again)
+	if got := receive(t, stream.C, 1); got[0].GetID() != post.Name || !got[0].EditedAt().Equal(again.Edited.At) {
+		t.Errorf("got %s edited at %s, want %s edited at %s", got[0].GetID(), got[0].EditedAt(), post.Name, again.Edited.At)
+	}
+	receiveNone(t, stream.C)
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
+
+func TestStreamModLog(t *testing.T) {
+	tests := []struct {
+		name string
+		opts mira.ModLogStreamOptions
+		// query are the filters sent to reddit, "" means the parameter is not sent
+		mod, typ string
+		// want are the indexes of the entries the stream sends
+		want []int
+	}{
+		{name: "unfiltered", want: []int{0, 1, 2, 3}},
+		{name: "mods", opts: mira.ModLogStreamOptions{Mods: []string{"alice", "bob"}}, mod: "alice,bob", want: []int{0, 1, 2}},
+		{name: "action", opts: mira.ModLogStreamOptions{Actions: []models.ModActionType{models.ModActionBanUser}}, typ: "banuser", want: []int{0, 1}},
+		{name: "actions & mod", opts: mira.ModLogStreamOptions{
+			Actions: []models.ModActionType{models.ModActionBanUser, models.ModActionRemoveLink},
+			Mods:    []string{"alice"},
+		}, mod: "alice", want: []int{0, 2}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "alice", Action: models.ModActionApproveLink})
+
+			stream, err := reddit.Subreddit("test").WithStreamOptions(fastStream).StreamModLogWithOptions(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries := []*models.ModAction{
+				srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "alice", Action: models.ModActionBanUser}),
+				srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "bob", Action: models.ModActionBanUser}),
+				srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "alice", Action: models.ModActionRemoveLink}),
+				srv.AddModAction(&models.ModAction{Subreddit: "test", Mod: "carol", Action: models.ModActionRemoveLink}),
+			}
+			got := receive(t, stream.C, len(tc.want))
+			for i, a := range got {
+				if want := entries[tc.want[i]]; a.ID != want.ID {
+					t.Errorf("entry %d is %s by %s, want %s by %s", i, a.Action, a.Mod, want.Action, want.Mod)
+				}
+			}
+			receiveNone(t, stream.C)
+			stopStream(t, stream.Close, stream.C, stream.Errors)
+
+			reqs := srv.RequestsTo("/r/test/about/log.json")
+			if len(reqs) == 0 {
+				t.Fatal("the mod log hasn't been requested")
+			}
+			for _, r := range reqs {
+				if r.Form.Has("mod") != (tc.mod != "") || r.Form.Get("mod") != tc.mod {
+					t.Fatalf("requested mod %q (sent: %v), want %q", r.Form.Get("mod"), r.Form.Has("mod"), tc.mod)
+				}
+				if r.Form.Has("type") != (tc.typ != "") || r.Form.Get("type") != tc.typ {
+					t.Fatalf("requested type %q (sent: %v), want %q", r.Form.Get("type"), r.Form.Has("type"), tc.typ)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamModMail(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	id := seedModmail(srv, 1)[0]
+	srv.AddModmailMessage(id, models.ModmailMessage{Body: "handled before", Author: models.ModmailAuthor{Name: "alice"}})
+
+	stream, err := reddit.Subreddit("test").WithStreamOptions(fastStream).StreamModMail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveNone(t, stream.C)
+
+	// a reply in an existing conversation
+	reply, _ := srv.AddModmailMessage(id, models.ModmailMessage{Body: "any news?", Author: models.ModmailAuthor{Name: "alice"}})
+	u := receive(t, stream.C, 1)[0]
+	if u.Conversation.ID != id || u.NewConversation || len(u.NewMessages) != 1 || u.NewMessages[0].ID != reply.ID {
+		t.Errorf("got conversation %s (new: %t) with %d new messages, want %s with the reply", u.Conversation.ID, u.NewConversation, len(u.NewMessages), id)
+	}
+	if len(u.Messages) != 2 || !u.IsNew(reply.ID) {
+		t.Errorf("got %d messages, want the old one & the reply", len(u.Messages))
+	}
+	receiveNone(t, stream.C)
+
+	// a new conversation & a private note
+	conv, err := reddit.Subreddit("test").CreateModMail("bob", "warning", "please stop", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u = receive(t, stream.C, 1)[0]
+	if u.Conversation.ID != conv.Conversation.ID || !u.NewConversation || len(u.NewMessages) != 1 {
+		t.Errorf("got conversation %s (new: %t) with %d new messages, want new %s with 1", u.Conversation.ID, u.NewConversation, len(u.NewMessages), conv.Conversation.ID)
+	}
+	if _, err := reddit.AddModMailNote(id, "alice asks a lot"); err != nil {
+		t.Fatal(err)
+	}
+	u = receive(t, stream.C, 1)[0]
+	if u.Conversation.ID != id || len(u.NewMessages) != 1 || !u.NewMessages[0].IsInternal {
+		t.Errorf("got conversation %s with %d new messages, want %s with the note", u.Conversation.ID, len(u.NewMessages), id)
+	}
+	receiveNone(t, stream.C)
+
+	// updates without new messages are skipped, and the conversation isn't requested
+	fetched := len(srv.RequestsTo("/api/mod/conversations/" + id))
+	updated := *srv.Modmail(id)
+	later := updated.CreatedAt().Add(time.Minute)
+	updated.Conversation.LastUpdated = &later
+	srv.AddModmail(&updated)
+	receiveNone(t, stream.C)
+	if n := len(srv.RequestsTo("/api/mod/conversations/" + id)); n != fetched {
+		t.Errorf("requested the conversation %d times for an update without messages", n-fetched)
+	}
+	stopStream(t, stream.Close, stream.C, stream.Errors)
+}
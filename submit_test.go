@@ -0,0 +1,185 @@
+package mira_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ttgmpsn/mira"
+	"github.com/ttgmpsn/mira/models"
+)
+
+func TestSubmitWithOptions(t *testing.T) {
+	noReplies := false
+	for _, tc := range []struct {
+		name string
+		opts mira.SubmitOptions
+		path string
+		want map[string][]string
+	}{
+		{
+			name: "self",
+			opts: mira.SubmitOptions{Title: "Hello", Text: "world", FlairID: "abc", NSFW: true},
+			path: "/api/submit",
+			want: map[string][]string{
+				"sr": {"test"}, "title": {"Hello"}, "kind": {"self"}, "text": {"world"}, "flair_id": {"abc"},
+				"nsfw": {"true"}, "spoiler": {"false"}, "sendreplies": {"true"}, "resubmit": {"false"},
+			},
+		},
+		{
+			name: "link",
+			opts: mira.SubmitOptions{Title: "Hello", URL: "https://example.com", Resubmit: true, SendReplies: &noReplies},
+			path: "/api/submit",
+			want: map[string][]string{
+				"kind": {"link"}, "url": {"https://example.com"}, "resubmit": {"true"}, "sendreplies": {"false"},
+			},
+		},
+		{
+			name: "poll",
+			opts: mira.SubmitOptions{
+				Title:   "Cats or dogs?",
+				Text:    "Vote!",
+				Poll:    &mira.PollOptions{Options: []string{"Cats", "Dogs", "Both"}, Duration: 3},
+				Spoiler: true,
+			},
+			path: "/api/submit_poll_post.json",
+			want: map[string][]string{
+				"sr": {"test"}, "title": {"Cats or dogs?"}, "text": {"Vote!"}, "options": {"Cats", "Dogs", "Both"},
+				"duration": {"3"}, "nsfw": {"false"}, "spoiler": {"true"}, "sendreplies": {"true"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			res, err := reddit.Subreddit("test").SubmitWithOptions(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if srv.Submission(res.Name) == nil {
+				t.Errorf("%s was not created", res.Name)
+			}
+			actions := srv.ActionsTo(tc.path)
+			if len(actions) != 1 {
+				t.Fatalf("got %d requests to %s, want 1", len(actions), tc.path)
+			}
+			for k, want := range tc.want {
+				if got := actions[0].Form[k]; strings.Join(got, "|") != strings.Join(want, "|") {
+					t.Errorf("%s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmitGallery(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	res, err := reddit.Subreddit("test").SubmitWithOptions(mira.SubmitOptions{
+		Title: "My pets",
+		Images: []mira.MediaUpload{
+			{Name: "cat.png", Data: strings.NewReader("cat"), Caption: "Tom"},
+			{Name: "dog.jpg", Data: strings.NewReader("dog"), OutboundURL: "https://example.com/rex"},
+		},
+		FlairText: "Pets",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploads := srv.Uploads()
+	if len(uploads) != 2 {
+		t.Fatalf("got %d uploads, want 2", len(uploads))
+	}
+	if uploads[0].MimeType != "image/png" || string(uploads[0].Data) != "cat" {
+		t.Errorf("first upload is %s %q, want image/png \"cat\"", uploads[0].MimeType, uploads[0].Data)
+	}
+	if uploads[1].MimeType != "image/jpeg" || string(uploads[1].Data) != "dog" {
+		t.Errorf("second upload is %s %q, want image/jpeg \"dog\"", uploads[1].MimeType, uploads[1].Data)
+	}
+
+	actions := srv.ActionsTo("/api/submit_gallery_post.json")
+	if len(actions) != 1 {
+		t.Fatalf("got %d gallery submissions, want 1", len(actions))
+	}
+	form := actions[0].Form
+	if form.Get("sr") != "test" || form.Get("title") != "My pets" || form.Get("flair_text") != "Pets" {
+		t.Errorf("got sr %q, title %q & flair text %q", form.Get("sr"), form.Get("title"), form.Get("flair_text"))
+	}
+	want := []models.GalleryItem{
+		{MediaID: uploads[0].AssetID, Caption: "Tom"},
+		{MediaID: uploads[1].AssetID, OutboundURL: "https://example.com/rex"},
+	}
+	if len(form["items"]) != len(want) {
+		t.Fatalf("got %d items, want %d", len(form["items"]), len(want))
+	}
+	for i, raw := range form["items"] {
+		var item models.GalleryItem
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			t.Fatal(err)
+		}
+		if item != want[i] {
+			t.Errorf("item %d is %+v, want %+v", i, item, want[i])
+		}
+	}
+
+	post, ok := srv.Submission(res.Name).(*models.Post)
+	if !ok || !post.IsGallery || len(post.GalleryData.Items) != 2 {
+		t.Errorf("%s is no gallery with 2 items", res.Name)
+	}
+}
+
+func TestSubmitErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts mira.SubmitOptions
+		err  string
+	}{
+		{
+			name: "multiple kinds",
+			opts: mira.SubmitOptions{Title: "Hello", URL: "https://example.com", Poll: &mira.PollOptions{}},
+			err:  "link & poll",
+		},
+		{
+			name: "poll options",
+			opts: mira.SubmitOptions{Title: "Hello", Poll: &mira.PollOptions{Options: []string{"Yes"}, Duration: 1}},
+			err:  "polls need 2 to 6 options, got 1",
+		},
+		{
+			name: "poll duration",
+			opts: mira.SubmitOptions{Title: "Hello", Poll: &mira.PollOptions{Options: []string{"Yes", "No"}, Duration: 8}},
+			err:  "polls can be open for 1 to 7 days, got 8",
+		},
+		{
+			name: "crosspost",
+			opts: mira.SubmitOptions{Title: "Hello", Crosspost: "t1_abc"},
+			err:  "'t1_abc' is no post",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, reddit := newTestServer(t)
+			_, err := reddit.Subreddit("test").SubmitWithOptions(tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("got error %v, want it to contain %q", err, tc.err)
+			}
+			if actions := srv.Actions(); len(actions) != 0 {
+				t.Errorf("got %d actions, want none", len(actions))
+			}
+		})
+	}
+}
+
+func TestUploadMediaFailed(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.Fail("/media-upload", 1, http.StatusForbidden, "<Error><Code>AccessDenied</Code></Error>")
+
+	_, err := reddit.Subreddit("test").SubmitWithOptions(mira.SubmitOptions{
+		Title:  "My cat",
+		Images: []mira.MediaUpload{{Name: "cat.png", Data: strings.NewReader("cat")}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("got error %v, want the status & answer of the storage", err)
+	}
+	if n := len(srv.ActionsTo("/api/submit")); n != 0 {
+		t.Errorf("got %d submissions, want none", n)
+	}
+}
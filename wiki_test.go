@@ -0,0 +1,143 @@
+package mira_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ttgmpsn/mira"
+)
+
+func TestEditWikiWithOptions(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.SetWiki("test", "index", "hello")
+	sub := reddit.Subreddit("test")
+
+	wiki, err := sub.Wiki("index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sub.EditWikiWithOptions("index", mira.WikiEditOptions{Content: "hello world", Reason: "more", Previous: wiki.RevisionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := srv.ActionsTo("/r/test/api/wiki/edit")
+	if len(actions) != 1 {
+		t.Fatalf("got %d edits, want 1", len(actions))
+	}
+	for k, v := range map[string]string{"page": "index", "content": "hello world", "reason": "more", "previous": wiki.RevisionID} {
+		if got := actions[0].Form.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if got := srv.Wiki("test", "index"); got.ContentMD != "hello world" {
+		t.Errorf("got content %q, want \"hello world\"", got.ContentMD)
+	}
+
+	// without Previous, edits overwrite the page
+	if err := sub.EditWiki("index", "replaced", "no questions asked"); err != nil {
+		t.Fatal(err)
+	}
+	if actions := srv.ActionsTo("/r/test/api/wiki/edit"); actions[1].Form.Has("previous") {
+		t.Errorf("sent previous %q without WikiEditOptions.Previous", actions[1].Form.Get("previous"))
+	}
+}
+
+func TestEditWikiConflict(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.SetWiki("test", "banned-domains", "* a.example.com")
+	sub := reddit.Subreddit("test")
+
+	wiki, err := sub.Wiki("banned-domains")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// someone else edits the page in the meantime
+	srv.SetWiki("test", "banned-domains", "* a.example.com\n* b.example.com")
+	current := srv.Wiki("test", "banned-domains")
+
+	err = sub.EditWikiWithOptions("banned-domains", mira.WikiEditOptions{
+		Content:  wiki.ContentMD + "\n* spam.example.com",
+		Previous: wiki.RevisionID,
+	})
+	var conflict *mira.WikiConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("got error %v, want a *WikiConflictError", err)
+	}
+	if conflict.NewRevision != current.RevisionID || conflict.NewContent != current.ContentMD {
+		t.Errorf("got revision %s with %q, want %s with %q", conflict.NewRevision, conflict.NewContent, current.RevisionID, current.ContentMD)
+	}
+	if !strings.Contains(conflict.Diff, "spam.example.com") {
+		t.Errorf("diff %q doesn't contain the rejected change", conflict.Diff)
+	}
+	var apiErr *mira.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("got error %v, want it to wrap a 409 *APIError", err)
+	}
+	if got := srv.Wiki("test", "banned-domains"); got.RevisionID != current.RevisionID {
+		t.Errorf("the page was changed to revision %s", got.RevisionID)
+	}
+
+	// retrying on top of the new revision works, as in the example of EditWikiWithOptions
+	err = sub.EditWikiWithOptions("banned-domains", mira.WikiEditOptions{
+		Content:  conflict.NewContent + "\n* spam.example.com",
+		Previous: conflict.NewRevision,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Wiki("test", "banned-domains").ContentMD; got != "* a.example.com\n* b.example.com\n* spam.example.com" {
+		t.Errorf("got content %q", got)
+	}
+}
+
+func TestWikiRevisions(t *testing.T) {
+	srv, reddit := newTestServer(t)
+	srv.SetWiki("test", "index", "one")
+	sub := reddit.Subreddit("test")
+	if err := sub.EditWiki("index", "two", "second"); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := sub.WikiRevisions("index", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revs))
+	}
+	if revs[0].Reason != "second" || revs[0].GetAuthor() != "miratest" || revs[0].Subreddit != "test" {
+		t.Errorf("got revision %q by %q in r/%s, want \"second\" by miratest in r/test", revs[0].Reason, revs[0].GetAuthor(), revs[0].Subreddit)
+	}
+
+	old, err := sub.WikiRevision("index", revs[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.ContentMD != "one" || old.DiffContent != "" {
+		t.Errorf("got revision with %q & diff %q, want \"one\" without diff", old.ContentMD, old.DiffContent)
+	}
+
+	diff, err := sub.WikiRevisionDiff("index", revs[1].ID, revs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.ContentMD != "two" || diff.RevisionID != revs[0].ID {
+		t.Errorf("got revision %s with %q, want %s with \"two\"", diff.RevisionID, diff.ContentMD, revs[0].ID)
+	}
+	if !strings.Contains(diff.DiffContent, "<del>one</del>") || !strings.Contains(diff.DiffContent, "<ins>two</ins>") {
+		t.Errorf("got diff %q, want one from \"one\" to \"two\"", diff.DiffContent)
+	}
+	requests := srv.RequestsTo("/r/test/wiki/index.json")
+	if last := requests[len(requests)-1]; last.Form.Get("v") != revs[1].ID || last.Form.Get("v2") != revs[0].ID {
+		t.Errorf("requested v %q & v2 %q, want %s & %s", last.Form.Get("v"), last.Form.Get("v2"), revs[1].ID, revs[0].ID)
+	}
+
+	if _, err := sub.WikiRevisionDiff("index", revs[1].ID, "nope"); err == nil {
+		t.Error("diffing to a missing revision succeeded")
+	}
+	if _, err := reddit.Redditor("alice").WikiRevisionDiff("index", revs[1].ID, revs[0].ID); err == nil {
+		t.Error("diffing the wiki of a redditor succeeded")
+	}
+}